@@ -10,7 +10,9 @@ import (
 	"os"
 	"strings"
 
-	jd "github.com/josephburnett/jd/lib"
+	"github.com/phux/apijc/har"
+	"github.com/phux/apijc/openapi"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 	"golang.org/x/time/rate"
 )
 
@@ -41,6 +43,16 @@ type App struct {
 	parser     parser
 	limiter    limiter
 	headers    Headers
+	client     *http.Client
+
+	equivalences  []Equivalence
+	urlNormalizer *urlNormalizer
+	schemas       map[string]*jsonschema.Schema
+	spec          *openapi.Document
+	showSecrets   bool
+	artifactsDir  string
+	dumps         map[string]dump
+	har           *har.HAR
 }
 
 func NewApp(
@@ -58,6 +70,8 @@ func NewApp(
 		parser:  parser,
 		limiter: rate.NewLimiter(rate.Limit(rateLimit), 1),
 		headers: headers,
+		client:  http.DefaultClient,
+		schemas: map[string]*jsonschema.Schema{},
 		Results: &Results{
 			Findings: []Finding{},
 		},
@@ -185,7 +199,7 @@ func (a *App) CheckTarget(target Target) (int, int, error) {
 	countPaths := len(relativePaths)
 	checkedPaths := 0
 	if err != nil {
-		a.addFinding(target.RelativePath, "", err)
+		a.addFinding(target, Finding{URL: target.RelativePath, HTTPMethod: target.HTTPMethod}, err)
 
 		return checkedPaths, countPaths,
 			fmt.Errorf(
@@ -202,25 +216,68 @@ func (a *App) CheckTarget(target Target) (int, int, error) {
 				fmt.Errorf("error while rate limiting: %w", err)
 		}
 
-		baseURL := a.BaseDomain + relativePath
-		baseBodyJSON, err := a.callTarget(baseURL, target)
+		if a.artifactsDir != "" {
+			a.dumps = map[string]dump{}
+		}
+
+		finding := Finding{
+			HTTPMethod: target.HTTPMethod,
+			BaseURL:    a.BaseDomain + relativePath,
+			NewURL:     a.NewDomain + relativePath,
+		}
+
+		operation, hasOperation := a.findOperation(target, relativePath)
+
+		baseBodyJSON, statusCode, err := a.callTarget(finding.BaseURL, target)
+		finding.BaseStatusCode = statusCode
 		if err != nil {
-			a.addFinding(baseURL, "", err)
+			finding.URL = finding.BaseURL
+			finding.Domain = DomainBase
+			a.addFinding(target, finding, err)
 
 			return checkedPaths, countPaths, nil
 		}
 
-		newURL := a.NewDomain + relativePath
-		newBodyJSON, err := a.callTarget(newURL, target)
+		newBodyJSON, statusCode, err := a.callTarget(finding.NewURL, target)
+		finding.NewStatusCode = statusCode
 		if err != nil {
-			a.addFinding(newURL, "", err)
+			finding.URL = finding.NewURL
+			finding.Domain = DomainNew
+			a.addFinding(target, finding, err)
 
 			return checkedPaths, countPaths, nil
 		}
 
-		diff := a.compareResponseBodies(baseBodyJSON, newBodyJSON)
-		if diff != "" {
-			a.addFinding(relativePath, diff, ErrJSONMismatch)
+		if target.ResponseSchema != nil {
+			err = a.validateResponseBodies(target, finding, baseBodyJSON, newBodyJSON)
+			if err != nil {
+				// the responses are still compared, an invalid schema must not
+				// hide regressions
+				schemaFinding := finding
+				schemaFinding.URL = relativePath
+				a.addFinding(target, schemaFinding, err)
+			}
+		}
+
+		if hasOperation {
+			err = a.validateAgainstSpec(target, operation, finding, baseBodyJSON, newBodyJSON)
+			if err != nil {
+				specFinding := finding
+				specFinding.URL = relativePath
+				a.addFinding(target, specFinding, err)
+			}
+		}
+
+		finding.URL = relativePath
+		finding.Diff, finding.Differences, err = a.compareResponseBodies(target, baseBodyJSON, newBodyJSON)
+		if err != nil {
+			finding.Diff, finding.Differences = "", nil
+			a.addFinding(target, finding, err)
+
+			return checkedPaths, countPaths, nil
+		}
+		if finding.Diff != "" {
+			a.addFinding(target, finding, ErrJSONMismatch)
 		}
 
 		checkedPaths++
@@ -229,38 +286,41 @@ func (a *App) CheckTarget(target Target) (int, int, error) {
 	return checkedPaths, countPaths, nil
 }
 
-func (a *App) compareResponseBodies(
-	baseBodyJSON, newBodyJSON []byte,
-) string {
-	first, _ := jd.ReadJsonString(string(baseBodyJSON))
-	second, _ := jd.ReadJsonString(string(newBodyJSON))
-	diff := first.Diff(second)
+func (a *App) AddURLs(urls URLs) {
+	a.URLs = urls
+}
 
-	return diff.Render()
+// AddEquivalences sets equivalence rules applied to the responses of all
+// targets, before the rules of the individual targets.
+func (a *App) AddEquivalences(equivalences ...Equivalence) {
+	a.equivalences = append(a.equivalences, equivalences...)
 }
 
-func (a *App) AddURLs(urls URLs) {
-	a.URLs = urls
+// NormalizeURLs enables rewriting BaseDomain, NewDomain and the given aliases
+// in string values of all responses to a placeholder before comparing them.
+// Query parameters of the rewritten URLs are sorted.
+func (a *App) NormalizeURLs(aliases ...string) {
+	a.urlNormalizer = newURLNormalizer(append([]string{a.BaseDomain, a.NewDomain}, aliases...)...)
 }
 
-func (a *App) callTarget(url string, target Target) ([]byte, error) {
+func (a *App) callTarget(url string, target Target) ([]byte, int, error) {
 	res, err := a.makeHTTPRequest(url, target)
 	if err != nil {
-		return nil, a.requestError(res, target, err)
+		return nil, 0, a.requestError(res, target, err)
 	}
 
+	defer res.Body.Close()
+
 	if res.StatusCode != target.ExpectedStatusCode {
-		return nil, a.statusCodeMissmatchError(target, res)
+		return nil, res.StatusCode, a.statusCodeMissmatchError(target, res)
 	}
 
-	defer res.Body.Close()
-
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, fmt.Errorf("could not read response body: %w", err)
+		return nil, res.StatusCode, fmt.Errorf("could not read response body: %w", err)
 	}
 
-	return body, nil
+	return body, res.StatusCode, nil
 }
 
 func (a *App) statusCodeMissmatchError(target Target, res *http.Response) error {
@@ -295,7 +355,16 @@ func (a *App) makeHTTPRequest(url string, target Target) (*http.Response, error)
 		return nil, err
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	var timer *harTimer
+	if a.har != nil {
+		req, timer = traceRequest(req)
+	}
+
+	res, err := a.client.Do(req)
+	if a.har != nil {
+		a.recordHAREntry(req, res, err, timer, target)
+	}
+	a.recordDump(url, req, res, target)
 	if err != nil {
 		return nil, fmt.Errorf("client: error making http request: %w", err)
 	}
@@ -323,7 +392,7 @@ func (a *App) buildRequest(target Target, url string) (*http.Request, error) {
 			)
 		}
 
-		body, err := os.ReadFile(*target.RequestBodyFile)
+		body, err := target.readRequestBodyFile()
 		if err != nil {
 			return nil, err
 		}
@@ -359,11 +428,25 @@ func (a *App) setHeaders(req *http.Request, url string, target Target) {
 	}
 }
 
-func (a *App) addFinding(url, diff string, err error) {
-	a.Results.Findings = append(
-		a.Results.Findings,
-		Finding{URL: url, Diff: diff, Error: fmt.Sprint(err)},
-	)
+// addFinding records finding of target with err. Its severity is derived from
+// its differences, findings without differences are breaking unless their
+// severity is set. The curl commands are only built for recorded findings, as
+// building them re-reads the request body.
+func (a *App) addFinding(target Target, finding Finding, err error) {
+	finding.Error = fmt.Sprint(err)
+	if finding.BaseURL != "" {
+		finding.BaseCurl = a.curlCommand(target, finding.BaseURL)
+		finding.NewCurl = a.curlCommand(target, finding.NewURL)
+	}
+	switch {
+	case len(finding.Differences) > 0:
+		finding.Severity = highestSeverity(finding.Differences)
+	case finding.Severity == "":
+		finding.Severity = SeverityBreaking
+	}
+	a.addArtifacts(&finding)
+
+	a.Results.Findings = append(a.Results.Findings, finding)
 }
 
 func (a *App) isBaseDomain(url string) bool {
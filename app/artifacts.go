@@ -0,0 +1,133 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// artifactNameUnsafe matches characters replaced in artifact directory names.
+var artifactNameUnsafe = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// Artifacts are the paths of the files containing the full requests and
+// responses of a finding.
+type Artifacts struct {
+	BaseRequest  string `json:"baseRequest,omitempty"`
+	BaseResponse string `json:"baseResponse,omitempty"`
+	NewRequest   string `json:"newRequest,omitempty"`
+	NewResponse  string `json:"newResponse,omitempty"`
+}
+
+// dump is a request and its response in HTTP/1.x wire format.
+type dump struct {
+	request  []byte
+	response []byte
+}
+
+// WriteArtifacts enables writing the full requests and responses (status
+// line, headers and body) of every finding to a directory per finding in dir.
+func (a *App) WriteArtifacts(dir string) {
+	a.artifactsDir = dir
+	a.dumps = map[string]dump{}
+}
+
+// recordDump keeps the sent req and res of the request to url for the
+// artifacts of a finding. Secret and interpolated values of target are
+// redacted like in the curl commands. The response body stays readable.
+func (a *App) recordDump(url string, req *http.Request, res *http.Response, target Target) {
+	if a.artifactsDir == "" {
+		return
+	}
+
+	// the body of req has been consumed by sending it
+	sent := req.Clone(req.Context())
+	if req.GetBody != nil {
+		sent.Body, _ = req.GetBody()
+	}
+	sent.URL = a.redactURL(target, req.URL)
+	if a.redactBody(target) && sent.Body != nil {
+		sent.Body = io.NopCloser(strings.NewReader(redacted))
+		sent.ContentLength = int64(len(redacted))
+	}
+
+	for name, values := range sent.Header {
+		if !a.redactHeader(target, name) {
+			continue
+		}
+		for i, value := range values {
+			values[i] = redactHeaderValue(value)
+		}
+	}
+
+	recorded := dump{}
+	recorded.request, _ = httputil.DumpRequestOut(sent, true)
+	if res != nil {
+		recorded.response, _ = httputil.DumpResponse(res, true)
+	}
+
+	a.dumps[url] = recorded
+}
+
+// writeArtifacts writes the recorded dumps of the requests of finding and
+// returns their paths.
+func (a *App) writeArtifacts(finding Finding) (*Artifacts, error) {
+	path := strings.Trim(artifactNameUnsafe.ReplaceAllString(finding.URL, "_"), "_")
+	name := fmt.Sprintf("%04d-%s-%s", len(a.Results.Findings)+1, finding.HTTPMethod, path)
+	if len(name) > 100 {
+		name = name[:100]
+	}
+
+	dir := filepath.Join(a.artifactsDir, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	artifacts := &Artifacts{}
+	files := []struct {
+		path    *string
+		name    string
+		content []byte
+	}{
+		{&artifacts.BaseRequest, "base.request.http", a.dumps[finding.BaseURL].request},
+		{&artifacts.BaseResponse, "base.response.http", a.dumps[finding.BaseURL].response},
+		{&artifacts.NewRequest, "new.request.http", a.dumps[finding.NewURL].request},
+		{&artifacts.NewResponse, "new.response.http", a.dumps[finding.NewURL].response},
+	}
+
+	for _, file := range files {
+		if file.content == nil {
+			continue
+		}
+
+		path := filepath.Join(dir, file.name)
+		if err := os.WriteFile(path, file.content, 0o644); err != nil {
+			return nil, err
+		}
+		*file.path = path
+	}
+
+	return artifacts, nil
+}
+
+// addArtifacts writes the artifacts of finding, if enabled. Errors are logged,
+// as the finding itself is more important than its artifacts.
+func (a *App) addArtifacts(finding *Finding) {
+	if a.artifactsDir == "" || finding.BaseURL == "" {
+		return
+	}
+
+	artifacts, err := a.writeArtifacts(*finding)
+	if err != nil {
+		log.Printf("could not write artifacts of %s: %s\n", finding.URL, err)
+
+		return
+	}
+
+	finding.Artifacts = artifacts
+}
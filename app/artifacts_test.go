@@ -0,0 +1,84 @@
+package app_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCheckTarget_Artifacts(t *testing.T) {
+	baseDomain := "http://localhost:1234"
+	newDomain := "http://localhost:5678"
+	dir := t.TempDir()
+
+	defer gock.Off()
+	gock.New(baseDomain).Post("/foo").Reply(200).SetHeader("X-Version", "1").BodyString(`{"id": 1}`)
+	gock.New(newDomain).Post("/foo").Reply(200).SetHeader("X-Version", "2").BodyString(`{"id": 2}`)
+
+	a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{
+		Global: app.HeaderKV{"Authorization": "Bearer secret"},
+	})
+	a.WriteArtifacts(dir)
+
+	_, _, err := a.CheckTarget(app.Target{
+		RelativePath:       "/foo",
+		HTTPMethod:         "POST",
+		ExpectedStatusCode: 200,
+		RequestBody:        stringPointer(`{"name":"foo"}`),
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, a.Results.Findings, 1)
+	artifactsDir := filepath.Join(dir, "0001-POST-foo")
+	assert.Equal(t, &app.Artifacts{
+		BaseRequest:  filepath.Join(artifactsDir, "base.request.http"),
+		BaseResponse: filepath.Join(artifactsDir, "base.response.http"),
+		NewRequest:   filepath.Join(artifactsDir, "new.request.http"),
+		NewResponse:  filepath.Join(artifactsDir, "new.response.http"),
+	}, a.Results.Findings[0].Artifacts)
+
+	request, err := os.ReadFile(a.Results.Findings[0].Artifacts.BaseRequest)
+	assert.NoError(t, err)
+	assert.Contains(t, string(request), "POST /foo HTTP/1.1\r\nHost: localhost:1234\r\n")
+	assert.Contains(t, string(request), "Authorization: Bearer REDACTED\r\n")
+	assert.Contains(t, string(request), "\r\n\r\n{\"name\":\"foo\"}")
+
+	response, err := os.ReadFile(a.Results.Findings[0].Artifacts.NewResponse)
+	assert.NoError(t, err)
+	assert.Contains(t, string(response), "HTTP/1.1 200 OK\r\n")
+	assert.Contains(t, string(response), "X-Version: 2\r\n")
+	assert.Contains(t, string(response), "\r\n\r\n{\"id\": 2}")
+}
+
+func TestCheckTarget_ArtifactsOfUnexpectedStatusCode(t *testing.T) {
+	baseDomain := "http://localhost:1234"
+	newDomain := "http://localhost:5678"
+	dir := t.TempDir()
+
+	defer gock.Off()
+	gock.New(baseDomain).Get("/foo").Reply(500).BodyString(`{"error": "boom"}`)
+
+	a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+	a.WriteArtifacts(dir)
+
+	_, _, err := a.CheckTarget(app.Target{
+		RelativePath:       "/foo",
+		HTTPMethod:         "GET",
+		ExpectedStatusCode: 200,
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, a.Results.Findings, 1)
+	artifacts := a.Results.Findings[0].Artifacts
+	assert.Empty(t, artifacts.NewRequest)
+	assert.Empty(t, artifacts.NewResponse)
+
+	response, err := os.ReadFile(artifacts.BaseResponse)
+	assert.NoError(t, err)
+	assert.Contains(t, string(response), `{"error": "boom"}`)
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	jd "github.com/josephburnett/jd/lib"
+)
+
+var ErrUnknownCompareMode = errors.New("unknown compareMode, must be one of exact|compatible|shape")
+
+const (
+	// CompareModeExact reports every difference between the responses.
+	CompareModeExact = "exact"
+	// CompareModeCompatible allows fields that are only present in the new
+	// response, i.e. additive, backwards compatible changes.
+	CompareModeCompatible = "compatible"
+)
+
+func (a *App) compareResponseBodies(
+	target Target,
+	baseBodyJSON, newBodyJSON []byte,
+) (string, []Difference, error) {
+	switch target.CompareMode {
+	case "", CompareModeExact, CompareModeCompatible, CompareModeShape:
+	default:
+		return "", nil, fmt.Errorf("%q: %w", target.CompareMode, ErrUnknownCompareMode)
+	}
+
+	first, second, err := a.prepareResponseBodies(target, baseBodyJSON, newBodyJSON)
+	if err != nil {
+		return "", nil, err
+	}
+
+	diff := first.Diff(second)
+
+	reported := jd.Diff{}
+	differences := []Difference{}
+	for _, element := range diff {
+		difference := classifyDiffElement(element)
+		if target.CompareMode == CompareModeShape {
+			difference = shapeDifference(difference, element)
+		}
+
+		if target.CompareMode == CompareModeCompatible && isAdditive(difference, element) {
+			continue
+		}
+
+		reported = append(reported, element)
+		differences = append(differences, difference)
+	}
+
+	return reported.Render(), differences, nil
+}
+
+// prepareResponseBodies parses both bodies, decodes embedded payloads,
+// applies the transformations, normalizes embedded URLs and equivalent values
+// and applies the field rules of target.
+//
+// Bodies that are not valid JSON are compared as they are, without any of
+// these steps, which is logged if target configures some of them.
+func (a *App) prepareResponseBodies(
+	target Target,
+	baseBodyJSON, newBodyJSON []byte,
+) (jd.JsonNode, jd.JsonNode, error) {
+	equivalences := append(append([]Equivalence{}, a.equivalences...), target.Equivalences...)
+
+	var baseDoc, newDoc interface{}
+	if json.Unmarshal(baseBodyJSON, &baseDoc) != nil || json.Unmarshal(newBodyJSON, &newDoc) != nil {
+		if a.urlNormalizer != nil || len(equivalences) > 0 || len(target.Fields) > 0 ||
+			len(target.TransformBase) > 0 || len(target.TransformNew) > 0 || target.CompareMode == CompareModeShape {
+			log.Printf(
+				"%s %s: response is not valid JSON, comparing it as it is without field rules, equivalences, transformations, shape mode and URL normalization\n",
+				target.HTTPMethod, target.RelativePath,
+			)
+		}
+
+		return rawJSONNode(baseBodyJSON), rawJSONNode(newBodyJSON), nil
+	}
+
+	baseDoc, err := a.prepareResponseBody(baseDoc, target.Fields, target.TransformBase, equivalences)
+	if err != nil {
+		return nil, nil, fmt.Errorf("base response: %w", err)
+	}
+
+	newDoc, err = a.prepareResponseBody(newDoc, target.Fields, target.TransformNew, equivalences)
+	if err != nil {
+		return nil, nil, fmt.Errorf("new response: %w", err)
+	}
+
+	baseDoc, newDoc, err = applyFieldRules(baseDoc, newDoc, target.Fields)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fields: %w", err)
+	}
+
+	if target.CompareMode == CompareModeShape {
+		baseDoc, newDoc = alignShapes(shapeOf(baseDoc), shapeOf(newDoc))
+	}
+
+	first, err := jd.NewJsonNode(baseDoc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	second, err := jd.NewJsonNode(newDoc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return first, second, nil
+}
+
+func (a *App) prepareResponseBody(
+	doc interface{},
+	fields []FieldRule,
+	transformations []Transformation,
+	equivalences []Equivalence,
+) (interface{}, error) {
+	doc, err := decodeFields(doc, fields)
+	if err != nil {
+		return nil, fmt.Errorf("fields: %w", err)
+	}
+
+	doc, err = applyTransformations(doc, transformations)
+	if err != nil {
+		return nil, fmt.Errorf("transformations: %w", err)
+	}
+
+	if a.urlNormalizer != nil {
+		doc = a.urlNormalizer.normalize(doc)
+	}
+
+	doc, err = applyEquivalences(doc, equivalences)
+	if err != nil {
+		return nil, fmt.Errorf("equivalences: %w", err)
+	}
+
+	return doc, nil
+}
+
+// rawJSONNode reads body as JSON if possible (an empty body is a void node),
+// otherwise as a single string value.
+func rawJSONNode(body []byte) jd.JsonNode {
+	node, err := jd.ReadJsonString(string(body))
+	if err != nil {
+		node, _ = jd.NewJsonNode(string(body))
+	}
+
+	return node
+}
+
+// isAdditive reports whether difference adds a field that is missing in the
+// base response, with either a value or null.
+func isAdditive(difference Difference, element jd.DiffElement) bool {
+	if !isObjectKey(element.Path) {
+		return false
+	}
+
+	return difference.Kind == DifferenceAdded ||
+		difference.Kind == DifferenceNullVsMissing && difference.OldType == jsonTypeMissing
+}
+
+// isObjectKey reports whether path points to a field of an object (as
+// opposed to an array element or the document root).
+func isObjectKey(path []jd.JsonNode) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	var key interface{}
+	_ = json.Unmarshal([]byte(path[len(path)-1].Json()), &key)
+	_, ok := key.(string)
+
+	return ok
+}
@@ -0,0 +1,199 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCheckTarget_CompareModes(t *testing.T) {
+	tests := []struct {
+		name                string
+		compareMode         string
+		baseBody            string
+		newBody             string
+		expectedDifferences []app.Difference
+		expectedError       string
+	}{
+		{
+			name:     "exact mode reports added fields",
+			baseBody: `{"id": 1}`,
+			newBody:  `{"id": 1, "name": "foo"}`,
+			expectedDifferences: []app.Difference{
+				{Path: "/name", Kind: app.DifferenceAdded, Severity: app.SeverityInfo, NewType: "string"},
+			},
+		},
+		{
+			name:        "compatible mode allows added fields",
+			compareMode: app.CompareModeCompatible,
+			baseBody:    `{"id": 1, "nested": {"a": 1}}`,
+			newBody:     `{"id": 1, "name": "foo", "nested": {"a": 1, "b": 2}}`,
+		},
+		{
+			name:        "compatible mode reports removed fields",
+			compareMode: app.CompareModeCompatible,
+			baseBody:    `{"id": 1, "name": "foo"}`,
+			newBody:     `{"id": 1, "extra": true}`,
+			expectedDifferences: []app.Difference{
+				{Path: "/name", Kind: app.DifferenceRemoved, Severity: app.SeverityBreaking, OldType: "string"},
+			},
+		},
+		{
+			name:        "compatible mode reports changed values and types",
+			compareMode: app.CompareModeCompatible,
+			baseBody:    `{"a": 1, "b": 2}`,
+			newBody:     `{"a": 3, "b": "2"}`,
+			expectedDifferences: []app.Difference{
+				{Path: "/a", Kind: app.DifferenceChanged, Severity: app.SeverityWarning, OldType: "number", NewType: "number"},
+				{Path: "/b", Kind: app.DifferenceTypeChanged, Severity: app.SeverityBreaking, OldType: "number", NewType: "string"},
+			},
+		},
+		{
+			name:        "compatible mode reports added array elements",
+			compareMode: app.CompareModeCompatible,
+			baseBody:    `{"items": [1]}`,
+			newBody:     `{"items": [1, 2]}`,
+			expectedDifferences: []app.Difference{
+				{Path: "/items/-", Kind: app.DifferenceAdded, Severity: app.SeverityInfo, NewType: "number"},
+			},
+		},
+		{
+			name:     "null vs missing",
+			baseBody: `{"a": null}`,
+			newBody:  `{"b": 1}`,
+			expectedDifferences: []app.Difference{
+				{Path: "/a", Kind: app.DifferenceNullVsMissing, Severity: app.SeverityWarning, OldType: "null"},
+				{Path: "/b", Kind: app.DifferenceAdded, Severity: app.SeverityInfo, NewType: "number"},
+			},
+		},
+		{
+			name:        "compatible mode allows new null fields",
+			compareMode: app.CompareModeCompatible,
+			baseBody:    `{}`,
+			newBody:     `{"a": null}`,
+		},
+		{
+			name:        "shape mode ignores values",
+			compareMode: app.CompareModeShape,
+			baseBody:    `{"sku": "a", "stock": 1, "prices": [{"amount": 1.5}, {"amount": 2}], "tags": []}`,
+			newBody:     `{"sku": "b", "stock": 7, "prices": [{"amount": 3}], "tags": ["x", "y"]}`,
+		},
+		{
+			name:        "shape mode reports type changes",
+			compareMode: app.CompareModeShape,
+			baseBody:    `{"stock": 1, "prices": [{"amount": 1.5}]}`,
+			newBody:     `{"stock": "1", "prices": [{"amount": "1.5"}]}`,
+			expectedDifferences: []app.Difference{
+				{Path: "/prices/0/amount", Kind: app.DifferenceTypeChanged, Severity: app.SeverityBreaking, OldType: "number", NewType: "string"},
+				{Path: "/stock", Kind: app.DifferenceTypeChanged, Severity: app.SeverityBreaking, OldType: "number", NewType: "string"},
+			},
+		},
+		{
+			name:        "shape mode reports structural changes",
+			compareMode: app.CompareModeShape,
+			baseBody:    `{"a": 1, "b": {"c": true}}`,
+			newBody:     `{"a": 2, "b": null, "d": "x"}`,
+			expectedDifferences: []app.Difference{
+				{Path: "/b", Kind: app.DifferenceTypeChanged, Severity: app.SeverityBreaking, OldType: "object", NewType: "null"},
+				{Path: "/d", Kind: app.DifferenceAdded, Severity: app.SeverityInfo, NewType: "string"},
+			},
+		},
+		{
+			name:        "shape mode merges array elements with optional and nullable fields",
+			compareMode: app.CompareModeShape,
+			baseBody:    `{"items": [{"id": 1, "note": "x", "parent": null}, {"id": 2, "parent": {"id": 1}}]}`,
+			newBody:     `{"items": [{"id": 3, "parent": {"id": 2}}, {"id": 4, "note": null, "parent": null}, {"id": 5}]}`,
+		},
+		{
+			name:        "shape mode reports changes of merged array elements",
+			compareMode: app.CompareModeShape,
+			baseBody:    `{"items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b", "note": "x"}]}`,
+			newBody:     `{"items": [{"id": "1", "note": "x"}, {"id": "2"}]}`,
+			expectedDifferences: []app.Difference{
+				{Path: "/items/0/id", Kind: app.DifferenceTypeChanged, Severity: app.SeverityBreaking, OldType: "number", NewType: "string"},
+				{Path: "/items/0/name", Kind: app.DifferenceRemoved, Severity: app.SeverityBreaking, OldType: "string"},
+			},
+		},
+		{
+			name:          "unknown compare mode",
+			compareMode:   "loose",
+			baseBody:      `{}`,
+			newBody:       `{}`,
+			expectedError: "\"loose\": unknown compareMode, must be one of exact|compatible|shape",
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			baseDomain := "http://localhost:1234"
+			newDomain := "http://localhost:5678"
+
+			defer gock.Off()
+			gock.New(baseDomain).Get("/foo").Reply(200).BodyString(tt.baseBody)
+			gock.New(newDomain).Get("/foo").Reply(200).BodyString(tt.newBody)
+
+			a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+			_, _, err := a.CheckTarget(app.Target{
+				RelativePath:       "/foo",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+				CompareMode:        tt.compareMode,
+			})
+			assert.NoError(t, err)
+
+			switch {
+			case tt.expectedError != "":
+				assert.Len(t, a.Results.Findings, 1)
+				assert.Equal(t, tt.expectedError, a.Results.Findings[0].Error)
+			case len(tt.expectedDifferences) > 0:
+				assert.Len(t, a.Results.Findings, 1)
+				assert.Equal(t, tt.expectedDifferences, a.Results.Findings[0].Differences)
+			default:
+				assert.Empty(t, a.Results.Findings)
+			}
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
+
+func TestCheckTarget_FindingDetails(t *testing.T) {
+	baseDomain := "http://localhost:1234"
+	newDomain := "http://localhost:5678"
+
+	defer gock.Off()
+	gock.New(baseDomain).Post("/foo").Reply(201).BodyString(`{"a": 1, "b": 2}`)
+	gock.New(newDomain).Post("/foo").Reply(201).BodyString(`{"a": 1, "b": 3}`)
+	gock.New(baseDomain).Post("/bar").Reply(500).BodyString(`{}`)
+
+	a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+	for _, path := range []string{"/foo", "/bar"} {
+		_, _, err := a.CheckTarget(app.Target{
+			RelativePath:       path,
+			HTTPMethod:         "POST",
+			ExpectedStatusCode: 201,
+		})
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, a.Results.Findings, 2)
+
+	mismatch := a.Results.Findings[0]
+	assert.Equal(t, "POST", mismatch.HTTPMethod)
+	assert.Equal(t, "http://localhost:1234/foo", mismatch.BaseURL)
+	assert.Equal(t, "http://localhost:5678/foo", mismatch.NewURL)
+	assert.Equal(t, 201, mismatch.BaseStatusCode)
+	assert.Equal(t, 201, mismatch.NewStatusCode)
+	assert.Equal(t, app.SeverityWarning, mismatch.Severity)
+
+	statusCode := a.Results.Findings[1]
+	assert.Equal(t, 500, statusCode.BaseStatusCode)
+	assert.Equal(t, 0, statusCode.NewStatusCode)
+	assert.Equal(t, app.SeverityBreaking, statusCode.Severity)
+
+	assert.Equal(t, []app.Finding{statusCode}, a.Results.Breaking())
+	assert.True(t, gock.IsDone())
+}
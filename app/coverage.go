@@ -0,0 +1,139 @@
+package app
+
+import (
+	"sort"
+
+	"github.com/phux/apijc/openapi"
+)
+
+// CoverageReport maps the targets of a urlFile to the operations of an
+// OpenAPI document.
+type CoverageReport struct {
+	Targets []TargetCoverage `json:"targets"`
+	// UncoveredOperations are never exercised by any target.
+	UncoveredOperations []string `json:"uncoveredOperations"`
+	// HappyPathOnlyOperations are only exercised by targets expecting a 2xx
+	// status code.
+	HappyPathOnlyOperations []OperationCoverage `json:"happyPathOnlyOperations"`
+	// UnmatchedTargets match no operation.
+	UnmatchedTargets []TargetCoverage `json:"unmatchedTargets"`
+}
+
+// TargetCoverage is a target after path expansion and the operation it
+// exercises, if any.
+type TargetCoverage struct {
+	HTTPMethod         string `json:"httpMethod"`
+	RelativePath       string `json:"relativePath"`
+	ExpectedStatusCode int    `json:"expectedStatusCode"`
+	Operation          string `json:"operation,omitempty"`
+}
+
+// OperationCoverage lists the documented responses of an operation that are
+// not exercised by any target.
+type OperationCoverage struct {
+	Operation            string   `json:"operation"`
+	UncoveredStatusCodes []string `json:"uncoveredStatusCodes"`
+}
+
+// Coverage maps all targets (after path expansion) to the operations of doc.
+// No requests are made.
+func (a *App) Coverage(doc *openapi.Document) (*CoverageReport, error) {
+	report := &CoverageReport{
+		Targets:                 []TargetCoverage{},
+		UncoveredOperations:     []string{},
+		HappyPathOnlyOperations: []OperationCoverage{},
+		UnmatchedTargets:        []TargetCoverage{},
+	}
+
+	targets := append([]Target{}, a.URLs.Targets...)
+	for _, name := range sortedSequenceNames(a.URLs.SequentialTargets) {
+		targets = append(targets, a.URLs.SequentialTargets[name]...)
+	}
+
+	statusCodes := map[string][]int{}
+	for _, target := range targets {
+		opts, err := a.buildOptsFromTarget(target)
+		if err != nil {
+			return nil, err
+		}
+
+		relativePaths, err := a.parser.ParsePath(target.RelativePath, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, relativePath := range relativePaths {
+			coverage := TargetCoverage{
+				HTTPMethod:         target.HTTPMethod,
+				RelativePath:       relativePath,
+				ExpectedStatusCode: target.ExpectedStatusCode,
+			}
+
+			operation, ok := doc.FindOperation(target.HTTPMethod, relativePath)
+			if !ok {
+				report.Targets = append(report.Targets, coverage)
+				report.UnmatchedTargets = append(report.UnmatchedTargets, coverage)
+
+				continue
+			}
+
+			coverage.Operation = operation.String()
+			report.Targets = append(report.Targets, coverage)
+			statusCodes[coverage.Operation] = append(statusCodes[coverage.Operation], target.ExpectedStatusCode)
+		}
+	}
+
+	for _, operation := range doc.Operations {
+		covered, ok := statusCodes[operation.String()]
+		if !ok {
+			report.UncoveredOperations = append(report.UncoveredOperations, operation.String())
+
+			continue
+		}
+
+		if !onlyHappyPath(covered) {
+			continue
+		}
+
+		report.HappyPathOnlyOperations = append(report.HappyPathOnlyOperations, OperationCoverage{
+			Operation:            operation.String(),
+			UncoveredStatusCodes: uncoveredStatusCodes(operation),
+		})
+	}
+
+	return report, nil
+}
+
+func onlyHappyPath(statusCodes []int) bool {
+	for _, statusCode := range statusCodes {
+		if statusCode < 200 || statusCode > 299 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// uncoveredStatusCodes returns the documented non-2xx responses of operation.
+func uncoveredStatusCodes(operation openapi.Operation) []string {
+	uncovered := []string{}
+	for _, response := range operation.Responses {
+		if len(response) == 3 && response[0] == '2' {
+			continue
+		}
+
+		uncovered = append(uncovered, response)
+	}
+
+	return uncovered
+}
+
+func sortedSequenceNames(sequentialTargets map[string][]Target) []string {
+	names := make([]string, 0, len(sequentialTargets))
+	for name := range sequentialTargets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
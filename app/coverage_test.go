@@ -0,0 +1,50 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/openapi"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp_Coverage(t *testing.T) {
+	doc, err := openapi.Load("../.testdata/openapi.yaml")
+	assert.NoError(t, err)
+
+	a := app.NewApp("", "", app.NewURLParser(), 1, app.Headers{})
+	a.AddURLs(app.URLs{
+		Targets: []app.Target{
+			{RelativePath: "/v1/items/{1-2}", HTTPMethod: "GET", ExpectedStatusCode: 200},
+			{RelativePath: "/v1/items/3", HTTPMethod: "GET", ExpectedStatusCode: 404},
+			{RelativePath: "/v1/unknown", HTTPMethod: "GET", ExpectedStatusCode: 200},
+		},
+		SequentialTargets: map[string][]app.Target{
+			"create": {
+				{RelativePath: "/v1/example", HTTPMethod: "POST", ExpectedStatusCode: 201},
+			},
+		},
+	})
+
+	report, err := a.Coverage(doc)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []app.TargetCoverage{
+		{HTTPMethod: "GET", RelativePath: "/v1/items/1", ExpectedStatusCode: 200, Operation: "GET /v1/items/{id}"},
+		{HTTPMethod: "GET", RelativePath: "/v1/items/2", ExpectedStatusCode: 200, Operation: "GET /v1/items/{id}"},
+		{HTTPMethod: "GET", RelativePath: "/v1/items/3", ExpectedStatusCode: 404, Operation: "GET /v1/items/{id}"},
+		{HTTPMethod: "GET", RelativePath: "/v1/unknown", ExpectedStatusCode: 200},
+		{HTTPMethod: "POST", RelativePath: "/v1/example", ExpectedStatusCode: 201, Operation: "POST /v1/example"},
+	}, report.Targets)
+	assert.Equal(t, []string{
+		"GET /v1/example",
+		"GET /v1/users/{userId}/orders/{orderId}",
+	}, report.UncoveredOperations)
+	assert.Equal(t, []app.OperationCoverage{
+		{Operation: "POST /v1/example", UncoveredStatusCodes: []string{"400"}},
+	}, report.HappyPathOnlyOperations)
+	assert.Equal(t, []app.TargetCoverage{
+		{HTTPMethod: "GET", RelativePath: "/v1/unknown", ExpectedStatusCode: 200},
+	}, report.UnmatchedTargets)
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// redacted replaces secret values in curl commands.
+const redacted = "REDACTED"
+
+// secretNames are parts of header and query parameter names whose values are
+// redacted in curl commands.
+var secretNames = []string{"auth", "cookie", "token", "secret", "password", "session", "key", "signature"}
+
+// ShowSecrets disables redacting credentials in the curl commands of findings.
+func (a *App) ShowSecrets() {
+	a.showSecrets = true
+}
+
+// curlCommand returns a curl command reproducing the request to url for
+// target, as sent by makeHTTPRequest. Secret and interpolated values are
+// redacted unless ShowSecrets is set, see redactURL and redactHeader. Body
+// files are referenced, unless their interpolated content is shown.
+func (a *App) curlCommand(target Target, url string) string {
+	req, err := a.buildRequest(target, url)
+	if err != nil {
+		return ""
+	}
+
+	parts := []string{"curl", "-X", req.Method, shellQuote(a.redactURL(target, req.URL).String())}
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range req.Header.Values(name) {
+			if a.redactHeader(target, name) {
+				value = redactHeaderValue(value)
+			}
+			parts = append(parts, "-H", shellQuote(name+": "+value))
+		}
+	}
+
+	switch {
+	case target.RequestBodyFile != nil && !(a.showSecrets && target.interpolated.body):
+		parts = append(parts, "--data-binary", shellQuote("@"+*target.RequestBodyFile))
+	case a.redactBody(target):
+		parts = append(parts, "--data-raw", shellQuote(redacted))
+	case req.Body != nil:
+		body, err := io.ReadAll(req.Body)
+		if err == nil {
+			parts = append(parts, "--data-raw", shellQuote(string(body)))
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// redactBody reports whether the request body of target is redacted, as it
+// contains interpolated variables, e.g. credentials from the environment.
+func (a *App) redactBody(target Target) bool {
+	return !a.showSecrets && target.interpolated.body
+}
+
+// redactHeader reports whether the value of the request header name of target
+// is redacted, as its name suggests a secret or it contains interpolated
+// variables.
+func (a *App) redactHeader(target Target, name string) bool {
+	return !a.showSecrets && (isSecret(name) || target.interpolated.headers[http.CanonicalHeaderKey(name)])
+}
+
+// redactURL returns a copy of u with the values of secret query parameters
+// and the interpolated query parameters and path segments of target redacted,
+// unless ShowSecrets is set.
+func (a *App) redactURL(target Target, u *url.URL) *url.URL {
+	if a.showSecrets {
+		return u
+	}
+
+	redactedURL := *u
+
+	segments := strings.Split(u.EscapedPath(), "/")
+	for i := range segments {
+		if target.interpolated.segments[len(segments)-1-i] {
+			segments[i] = redacted
+		}
+	}
+	redactedURL.RawPath = strings.Join(segments, "/")
+	redactedURL.Path, _ = url.PathUnescape(redactedURL.RawPath)
+
+	if u.RawQuery != "" {
+		parameters := strings.Split(u.RawQuery, "&")
+		for i, parameter := range parameters {
+			name, _, ok := strings.Cut(parameter, "=")
+			if ok && (isSecret(name) || target.interpolated.query[name]) {
+				parameters[i] = name + "=" + redacted
+			}
+		}
+		redactedURL.RawQuery = strings.Join(parameters, "&")
+	}
+
+	return &redactedURL
+}
+
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, secretName := range secretNames {
+		if strings.Contains(name, secretName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactHeaderValue keeps the scheme of authorization values, e.g.
+// "Bearer REDACTED".
+func redactHeaderValue(value string) string {
+	if scheme, _, ok := strings.Cut(value, " "); ok {
+		switch strings.ToLower(scheme) {
+		case "basic", "bearer", "digest", "token":
+			return scheme + " " + redacted
+		}
+	}
+
+	return redacted
+}
+
+// shellQuote quotes value for POSIX shells.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
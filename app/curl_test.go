@@ -0,0 +1,64 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCheckTarget_CurlCommands(t *testing.T) {
+	tests := []struct {
+		name            string
+		showSecrets     bool
+		expectedBaseCmd string
+		expectedNewCmd  string
+	}{
+		{
+			name:            "secrets are redacted",
+			expectedBaseCmd: `curl -X POST 'http://localhost:1234/foo?api_key=REDACTED&page=1' -H 'Authorization: Bearer REDACTED' -H 'Content-Type: application/json' -H 'X-Env: base' --data-raw '{"name":"o'\''neil"}'`,
+			expectedNewCmd:  `curl -X POST 'http://localhost:5678/foo?api_key=REDACTED&page=1' -H 'Authorization: Bearer REDACTED' -H 'Content-Type: application/json' -H 'X-Env: new' --data-raw '{"name":"o'\''neil"}'`,
+		},
+		{
+			name:            "secrets are shown",
+			showSecrets:     true,
+			expectedBaseCmd: `curl -X POST 'http://localhost:1234/foo?api_key=abc&page=1' -H 'Authorization: Bearer base-token' -H 'Content-Type: application/json' -H 'X-Env: base' --data-raw '{"name":"o'\''neil"}'`,
+			expectedNewCmd:  `curl -X POST 'http://localhost:5678/foo?api_key=abc&page=1' -H 'Authorization: Bearer new-token' -H 'Content-Type: application/json' -H 'X-Env: new' --data-raw '{"name":"o'\''neil"}'`,
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			baseDomain := "http://localhost:1234"
+			newDomain := "http://localhost:5678"
+
+			defer gock.Off()
+			gock.New(baseDomain).Post("/foo").Reply(200).BodyString(`{"id": 1}`)
+			gock.New(newDomain).Post("/foo").Reply(200).BodyString(`{"id": 2}`)
+
+			a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{
+				Global:     app.HeaderKV{"Content-Type": "application/json"},
+				BaseDomain: app.HeaderKV{"Authorization": "Bearer base-token", "X-Env": "base"},
+				NewDomain:  app.HeaderKV{"Authorization": "Bearer new-token", "X-Env": "new"},
+			})
+			if tt.showSecrets {
+				a.ShowSecrets()
+			}
+
+			_, _, err := a.CheckTarget(app.Target{
+				RelativePath:       "/foo?api_key=abc&page=1",
+				HTTPMethod:         "POST",
+				ExpectedStatusCode: 200,
+				RequestBody:        stringPointer(`{"name":"o'neil"}`),
+			})
+			assert.NoError(t, err)
+
+			assert.Len(t, a.Results.Findings, 1)
+			assert.Equal(t, tt.expectedBaseCmd, a.Results.Findings[0].BaseCurl)
+			assert.Equal(t, tt.expectedNewCmd, a.Results.Findings[0].NewCurl)
+		})
+	}
+}
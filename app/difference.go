@@ -0,0 +1,150 @@
+package app
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	jd "github.com/josephburnett/jd/lib"
+)
+
+const (
+	DifferenceAdded         = "added"
+	DifferenceRemoved       = "removed"
+	DifferenceChanged       = "changed"
+	DifferenceTypeChanged   = "type-changed"
+	DifferenceNullVsMissing = "null-vs-missing"
+)
+
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityBreaking = "breaking"
+)
+
+const (
+	jsonTypeMissing = ""
+	jsonTypeNull    = "null"
+)
+
+// severities maps each kind of difference to its severity. Only changes that
+// can break existing clients of the base API are breaking.
+var severities = map[string]string{
+	DifferenceAdded:         SeverityInfo,
+	DifferenceChanged:       SeverityWarning,
+	DifferenceNullVsMissing: SeverityWarning,
+	DifferenceRemoved:       SeverityBreaking,
+	DifferenceTypeChanged:   SeverityBreaking,
+}
+
+// Difference is a single classified hunk of the diff between the base and
+// the new response body.
+type Difference struct {
+	Path     string `json:"path"`
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+	OldType  string `json:"oldType,omitempty"`
+	NewType  string `json:"newType,omitempty"`
+}
+
+func classifyDiffElement(element jd.DiffElement) Difference {
+	oldType := jsonNodeType(element.OldValues)
+	newType := jsonNodeType(element.NewValues)
+
+	kind := DifferenceChanged
+	switch {
+	case oldType == jsonTypeMissing && newType == jsonTypeNull,
+		oldType == jsonTypeNull && newType == jsonTypeMissing:
+		kind = DifferenceNullVsMissing
+	case oldType == jsonTypeMissing:
+		kind = DifferenceAdded
+	case newType == jsonTypeMissing:
+		kind = DifferenceRemoved
+	case oldType != newType:
+		kind = DifferenceTypeChanged
+	}
+
+	return Difference{
+		Path:     renderJSONPointer(element.Path),
+		Kind:     kind,
+		Severity: severities[kind],
+		OldType:  oldType,
+		NewType:  newType,
+	}
+}
+
+func highestSeverity(differences []Difference) string {
+	rank := map[string]int{SeverityInfo: 0, SeverityWarning: 1, SeverityBreaking: 2}
+
+	highest := SeverityInfo
+	for _, difference := range differences {
+		if rank[difference.Severity] > rank[highest] {
+			highest = difference.Severity
+		}
+	}
+
+	return highest
+}
+
+// jsonNodeType returns the JSON type of the first of values, or
+// jsonTypeMissing if there is no (non-void) value.
+func jsonNodeType(values []jd.JsonNode) string {
+	if len(values) == 0 {
+		return jsonTypeMissing
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(values[0].Json()), &value); err != nil {
+		return jsonTypeMissing
+	}
+
+	return jsonType(value)
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return jsonTypeNull
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+
+	return "object"
+}
+
+// renderJSONPointer renders a jd diff path as a JSON pointer (RFC 6901).
+func renderJSONPointer(path []jd.JsonNode) string {
+	escape := strings.NewReplacer("~", "~0", "/", "~1")
+
+	var builder strings.Builder
+	for _, element := range path {
+		builder.WriteString("/")
+
+		var value interface{}
+		raw := element.Json()
+		_ = json.Unmarshal([]byte(raw), &value)
+
+		switch typed := value.(type) {
+		case string:
+			builder.WriteString(escape.Replace(typed))
+		case float64:
+			// jd appends to arrays at index -1, JSON pointer uses "-"
+			if typed < 0 {
+				builder.WriteString("-")
+
+				continue
+			}
+			builder.WriteString(strconv.FormatFloat(typed, 'f', -1, 64))
+		default:
+			builder.WriteString(escape.Replace(raw))
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,103 @@
+package app
+
+import "strconv"
+
+// Equivalence defines values that are treated as equal when comparing the
+// responses. Without a Path it applies to the whole response body, otherwise
+// to the value at Path (a JSON pointer, "*" matches all children) and
+// everything below it.
+type Equivalence struct {
+	Path              string `json:"path,omitempty"`
+	NullEqualsMissing bool   `json:"nullEqualsMissing,omitempty"`
+	EmptyEqualsNull   bool   `json:"emptyEqualsNull,omitempty"`
+}
+
+func applyEquivalences(doc interface{}, equivalences []Equivalence) (interface{}, error) {
+	for _, equivalence := range equivalences {
+		path, err := parseJSONPath(equivalence.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(path) == 0 {
+			doc = equivalence.normalize(doc)
+
+			continue
+		}
+
+		// the parents of the values at path are modified, so that null values
+		// are only removed from objects
+		parentPath, segment := path[:len(path)-1], path[len(path)-1]
+		doc, _, err = modifyPath(doc, parentPath, func(parent interface{}) (interface{}, bool, error) {
+			return equivalence.normalizeChildren(parent, segment), true, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+// normalizeChildren normalizes the children of parent matching segment. Null
+// object members are removed if NullEqualsMissing is set, null array elements
+// are kept, as removing them would shift the indexes of the following ones
+// and hide differences.
+func (e Equivalence) normalizeChildren(parent interface{}, segment string) interface{} {
+	switch typed := parent.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if segment != jsonPathWildcard && segment != key {
+				continue
+			}
+
+			child = e.normalize(child)
+			if child == nil && e.NullEqualsMissing {
+				delete(typed, key)
+
+				continue
+			}
+			typed[key] = child
+		}
+	case []interface{}:
+		for i, child := range typed {
+			if segment == jsonPathWildcard || segment == strconv.Itoa(i) {
+				typed[i] = e.normalize(child)
+			}
+		}
+	}
+
+	return parent
+}
+
+// normalize rewrites value so that equivalent values have the same
+// representation: empty arrays and objects become null, null object fields
+// are removed.
+func (e Equivalence) normalize(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			child = e.normalize(child)
+			if child == nil && e.NullEqualsMissing {
+				delete(typed, key)
+
+				continue
+			}
+			typed[key] = child
+		}
+
+		if len(typed) == 0 && e.EmptyEqualsNull {
+			return nil
+		}
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = e.normalize(child)
+		}
+
+		if len(typed) == 0 && e.EmptyEqualsNull {
+			return nil
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,108 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCheckTarget_WithEquivalences(t *testing.T) {
+	tests := []struct {
+		name               string
+		baseBody           string
+		newBody            string
+		globalEquivalences []app.Equivalence
+		equivalences       []app.Equivalence
+		expectedDiff       string
+	}{
+		{
+			name:         "null and missing differ by default",
+			baseBody:     `{"a": 1}`,
+			newBody:      `{"a": 1, "b": null}`,
+			expectedDiff: "@ [\"b\"]\n+ null\n",
+		},
+		{
+			name:     "null equals missing globally",
+			baseBody: `{"a": 1, "nested": [{"c": 1}]}`,
+			newBody:  `{"a": 1, "b": null, "nested": [{"c": 1, "d": null}]}`,
+			globalEquivalences: []app.Equivalence{
+				{NullEqualsMissing: true},
+			},
+		},
+		{
+			name:     "empty array equals null on target",
+			baseBody: `{"items": null, "tags": {}}`,
+			newBody:  `{"items": [], "tags": null}`,
+			equivalences: []app.Equivalence{
+				{EmptyEqualsNull: true},
+			},
+		},
+		{
+			name:     "empty equals missing when both rules are enabled",
+			baseBody: `{"a": 1}`,
+			newBody:  `{"a": 1, "items": [], "meta": {"x": null}}`,
+			equivalences: []app.Equivalence{
+				{EmptyEqualsNull: true, NullEqualsMissing: true},
+			},
+		},
+		{
+			name:     "rule only applies to its path",
+			baseBody: `{"a": {}, "b": {}}`,
+			newBody:  `{"a": {"x": null}, "b": {"x": null}}`,
+			equivalences: []app.Equivalence{
+				{Path: "/a", NullEqualsMissing: true},
+			},
+			expectedDiff: "@ [\"b\",\"x\"]\n+ null\n",
+		},
+		{
+			name:     "rule on a wildcard path",
+			baseBody: `{"items": [{"id": 1}, {"id": 2}]}`,
+			newBody:  `{"items": [{"id": 1, "x": null}, {"id": 2}]}`,
+			equivalences: []app.Equivalence{
+				{Path: "/items/*/x", NullEqualsMissing: true},
+			},
+		},
+		{
+			name:     "null array elements are kept",
+			baseBody: `{"items": [1, null, 2]}`,
+			newBody:  `{"items": [1, 2]}`,
+			equivalences: []app.Equivalence{
+				{Path: "/items/*", NullEqualsMissing: true},
+			},
+			expectedDiff: "@ [\"items\",2]\n- 2\n@ [\"items\",1]\n- null\n+ 2\n",
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			baseDomain := "http://localhost:1234"
+			newDomain := "http://localhost:5678"
+
+			defer gock.Off()
+			gock.New(baseDomain).Get("/foo").Reply(200).BodyString(tt.baseBody)
+			gock.New(newDomain).Get("/foo").Reply(200).BodyString(tt.newBody)
+
+			a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+			a.AddEquivalences(tt.globalEquivalences...)
+			_, _, err := a.CheckTarget(app.Target{
+				RelativePath:       "/foo",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+				Equivalences:       tt.equivalences,
+			})
+			assert.NoError(t, err)
+
+			if tt.expectedDiff == "" {
+				assert.Empty(t, a.Results.Findings)
+			} else {
+				assert.Len(t, a.Results.Findings, 1)
+				assert.Equal(t, tt.expectedDiff, a.Results.Findings[0].Diff)
+			}
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrUnknownCompareAs = errors.New("unknown compareAs, must be datetime")
+	ErrInvalidTolerance = errors.New("invalid tolerance, must be a duration like 2s")
+	ErrUnknownDecode    = errors.New("unknown decode, must be one of json-string|base64|base64-json")
+)
+
+// CompareAsDatetime compares date strings as instants in time.
+const CompareAsDatetime = "datetime"
+
+const (
+	// DecodeJSONString decodes a string containing JSON.
+	DecodeJSONString = "json-string"
+	// DecodeBase64 decodes a base64 encoded string.
+	DecodeBase64 = "base64"
+	// DecodeBase64JSON decodes a base64 encoded string containing JSON.
+	DecodeBase64JSON = "base64-json"
+)
+
+// FieldRule changes how the values at Path (a JSON pointer, "*" matches all
+// children) are compared.
+type FieldRule struct {
+	Path      string `json:"path"`
+	CompareAs string `json:"compareAs,omitempty"`
+	// Layouts are Go time layouts to parse datetimes with, default RFC 3339.
+	Layouts []string `json:"layouts,omitempty"`
+	// Tolerance is the maximum difference between two datetimes that are
+	// still considered equal, e.g. 2s.
+	Tolerance string `json:"tolerance,omitempty"`
+	// Decode decodes the values before comparing them, so embedded JSON is
+	// diffed structurally.
+	Decode string `json:"decode,omitempty"`
+}
+
+// decodeFields decodes the values of doc according to the decode hints of
+// rules. Values that cannot be decoded are kept as they are.
+func decodeFields(doc interface{}, rules []FieldRule) (interface{}, error) {
+	for _, rule := range rules {
+		if rule.Decode == "" {
+			continue
+		}
+
+		path, err := parseJSONPath(rule.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		switch rule.Decode {
+		case DecodeJSONString, DecodeBase64, DecodeBase64JSON:
+		default:
+			return nil, fmt.Errorf("%s: %q: %w", rule.Path, rule.Decode, ErrUnknownDecode)
+		}
+
+		doc, _, err = modifyPath(doc, path, func(value interface{}) (interface{}, bool, error) {
+			return rule.decode(value), true, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+func (r FieldRule) decode(value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	if r.Decode == DecodeBase64 || r.Decode == DecodeBase64JSON {
+		decoded, ok := decodeBase64(str)
+		if !ok {
+			return value
+		}
+		if r.Decode == DecodeBase64 {
+			return decoded
+		}
+		str = decoded
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(str), &decoded); err != nil {
+		return value
+	}
+
+	return decoded
+}
+
+func decodeBase64(value string) (string, bool) {
+	for _, encoding := range []*base64.Encoding{
+		base64.StdEncoding,
+		base64.URLEncoding,
+		base64.RawStdEncoding,
+		base64.RawURLEncoding,
+	} {
+		if decoded, err := encoding.DecodeString(value); err == nil {
+			return string(decoded), true
+		}
+	}
+
+	return "", false
+}
+
+func applyFieldRules(baseDoc, newDoc interface{}, rules []FieldRule) (interface{}, interface{}, error) {
+	for _, rule := range rules {
+		path, err := parseJSONPath(rule.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch rule.CompareAs {
+		case "":
+		case CompareAsDatetime:
+			newDoc, err = rule.compareDatetimes(baseDoc, newDoc, path)
+		default:
+			err = fmt.Errorf("%q: %w", rule.CompareAs, ErrUnknownCompareAs)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", rule.Path, err)
+		}
+	}
+
+	return baseDoc, newDoc, nil
+}
+
+// compareDatetimes replaces datetimes in newDoc with the value of baseDoc if
+// both are the same instant (within the tolerance), so they are not reported
+// as different.
+func (r FieldRule) compareDatetimes(baseDoc, newDoc interface{}, path jsonPath) (interface{}, error) {
+	var tolerance time.Duration
+	if r.Tolerance != "" {
+		var err error
+		tolerance, err = time.ParseDuration(r.Tolerance)
+		if err != nil || tolerance < 0 {
+			return nil, fmt.Errorf("%q: %w", r.Tolerance, ErrInvalidTolerance)
+		}
+	}
+
+	for _, concretePath := range path.resolve(baseDoc) {
+		baseValue, _ := getPath(baseDoc, concretePath)
+		newValue, ok := getPath(newDoc, concretePath)
+		if !ok {
+			continue
+		}
+
+		baseTime, baseOK := r.parseDatetime(baseValue)
+		newTime, newOK := r.parseDatetime(newValue)
+		if !baseOK || !newOK {
+			continue
+		}
+
+		difference := baseTime.Sub(newTime)
+		if difference < 0 {
+			difference = -difference
+		}
+		if difference > tolerance {
+			continue
+		}
+
+		var err error
+		newDoc, err = setPath(newDoc, concretePath, baseValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newDoc, nil
+}
+
+func (r FieldRule) parseDatetime(value interface{}) (time.Time, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	layouts := r.Layouts
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339Nano}
+	}
+
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, str); err == nil {
+			return parsed, true
+		}
+	}
+
+	return time.Time{}, false
+}
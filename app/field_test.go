@@ -0,0 +1,147 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCheckTarget_WithFieldRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		baseBody      string
+		newBody       string
+		fields        []app.FieldRule
+		expectedDiff  string
+		expectedError string
+	}{
+		{
+			name:         "datetimes in different zones differ as strings",
+			baseBody:     `{"createdAt": "2024-01-02T03:04:05Z"}`,
+			newBody:      `{"createdAt": "2024-01-02T04:04:05+01:00"}`,
+			expectedDiff: "@ [\"createdAt\"]\n- \"2024-01-02T03:04:05Z\"\n+ \"2024-01-02T04:04:05+01:00\"\n",
+		},
+		{
+			name:     "same instant in different zones",
+			baseBody: `{"createdAt": "2024-01-02T03:04:05Z"}`,
+			newBody:  `{"createdAt": "2024-01-02T04:04:05+01:00"}`,
+			fields: []app.FieldRule{
+				{Path: "/createdAt", CompareAs: app.CompareAsDatetime},
+			},
+		},
+		{
+			name:     "within tolerance in array elements",
+			baseBody: `{"items": [{"at": "2024-01-02T03:04:05Z"}, {"at": "2024-01-02T03:04:05Z"}]}`,
+			newBody:  `{"items": [{"at": "2024-01-02T03:04:06Z"}, {"at": "2024-01-02T03:04:03.5Z"}]}`,
+			fields: []app.FieldRule{
+				{Path: "/items/*/at", CompareAs: app.CompareAsDatetime, Tolerance: "2s"},
+			},
+		},
+		{
+			name:     "outside tolerance",
+			baseBody: `{"at": "2024-01-02T03:04:05Z"}`,
+			newBody:  `{"at": "2024-01-02T03:04:08Z"}`,
+			fields: []app.FieldRule{
+				{Path: "/at", CompareAs: app.CompareAsDatetime, Tolerance: "2s"},
+			},
+			expectedDiff: "@ [\"at\"]\n- \"2024-01-02T03:04:05Z\"\n+ \"2024-01-02T03:04:08Z\"\n",
+		},
+		{
+			name:     "custom layouts",
+			baseBody: `{"at": "02.01.2024 03:04"}`,
+			newBody:  `{"at": "2024-01-02T03:04:00Z"}`,
+			fields: []app.FieldRule{
+				{Path: "/at", CompareAs: app.CompareAsDatetime, Layouts: []string{"02.01.2006 15:04", "2006-01-02T15:04:05Z07:00"}},
+			},
+		},
+		{
+			name:     "json strings are diffed structurally",
+			baseBody: `{"payload": "{\"a\":1,\"b\":{\"c\":true}}"}`,
+			newBody:  `{"payload": "{\"b\": {\"c\": false}, \"a\": 1}"}`,
+			fields: []app.FieldRule{
+				{Path: "/payload", Decode: app.DecodeJSONString},
+			},
+			expectedDiff: "@ [\"payload\",\"b\",\"c\"]\n- true\n+ false\n",
+		},
+		{
+			name:     "base64 strings are decoded",
+			baseBody: `{"blobs": ["aGVsbG8=", "d29ybGQ"]}`,
+			newBody:  `{"blobs": ["aGVsbG8", "d29ybGQ="]}`,
+			fields: []app.FieldRule{
+				{Path: "/blobs/*", Decode: app.DecodeBase64},
+			},
+		},
+		{
+			name:     "base64 json strings are diffed structurally",
+			baseBody: `{"data": "eyJhIjoxLCJiIjpbMSwyXX0="}`,
+			newBody:  `{"data": "eyJiIjpbMSwyXSwiYSI6Mn0="}`,
+			fields: []app.FieldRule{
+				{Path: "/data", Decode: app.DecodeBase64JSON},
+			},
+			expectedDiff: "@ [\"data\",\"a\"]\n- 1\n+ 2\n",
+		},
+		{
+			name:     "undecodable values are compared as they are",
+			baseBody: `{"payload": "not json"}`,
+			newBody:  `{"payload": "{}"}`,
+			fields: []app.FieldRule{
+				{Path: "/payload", Decode: app.DecodeJSONString},
+			},
+			expectedDiff: "@ [\"payload\"]\n- \"not json\"\n+ {}\n",
+		},
+		{
+			name:     "unknown decode",
+			baseBody: `{}`,
+			newBody:  `{}`,
+			fields: []app.FieldRule{
+				{Path: "/payload", Decode: "gzip"},
+			},
+			expectedError: "base response: fields: /payload: \"gzip\": unknown decode, must be one of json-string|base64|base64-json",
+		},
+		{
+			name:     "invalid tolerance",
+			baseBody: `{"at": "2024-01-02T03:04:05Z"}`,
+			newBody:  `{"at": "2024-01-02T03:04:05Z"}`,
+			fields: []app.FieldRule{
+				{Path: "/at", CompareAs: app.CompareAsDatetime, Tolerance: "soon"},
+			},
+			expectedError: "fields: /at: \"soon\": invalid tolerance, must be a duration like 2s",
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			baseDomain := "http://localhost:1234"
+			newDomain := "http://localhost:5678"
+
+			defer gock.Off()
+			gock.New(baseDomain).Get("/foo").Reply(200).BodyString(tt.baseBody)
+			gock.New(newDomain).Get("/foo").Reply(200).BodyString(tt.newBody)
+
+			a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+			_, _, err := a.CheckTarget(app.Target{
+				RelativePath:       "/foo",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+				Fields:             tt.fields,
+			})
+			assert.NoError(t, err)
+
+			switch {
+			case tt.expectedError != "":
+				assert.Len(t, a.Results.Findings, 1)
+				assert.Equal(t, tt.expectedError, a.Results.Findings[0].Error)
+			case tt.expectedDiff != "":
+				assert.Len(t, a.Results.Findings, 1)
+				assert.Equal(t, tt.expectedDiff, a.Results.Findings[0].Diff)
+			default:
+				assert.Empty(t, a.Results.Findings)
+			}
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
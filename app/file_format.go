@@ -0,0 +1,76 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// decodeFile unmarshals content into v, detecting the format by the file
+// extension of path: .yaml/.yml, .toml and JSON otherwise. YAML and TOML are
+// converted to JSON first, so the JSON field names apply to all formats.
+func decodeFile(path string, content []byte, v interface{}) error {
+	var (
+		doc interface{}
+		err error
+	)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &doc)
+	case ".toml":
+		var table map[string]interface{}
+		err = toml.Unmarshal(content, &table)
+		doc = table
+	default:
+		return json.Unmarshal(content, v)
+	}
+	if err != nil {
+		return err
+	}
+
+	content, err = json.Marshal(stringKeys(doc))
+	if err != nil {
+		return fmt.Errorf("cannot convert to JSON: %w", err)
+	}
+
+	return json.Unmarshal(content, v)
+}
+
+// stringKeys converts the map[interface{}]interface{} YAML produces for
+// non-string keys (e.g. unquoted numbers) into map[string]interface{}.
+func stringKeys(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			typed[key] = stringKeys(child)
+		}
+
+		return typed
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(typed))
+		for key, child := range typed {
+			object[fmt.Sprint(key)] = stringKeys(child)
+		}
+
+		return object
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = stringKeys(child)
+		}
+	case []map[string]interface{}:
+		// TOML arrays of tables
+		list := make([]interface{}, len(typed))
+		for i, child := range typed {
+			list[i] = stringKeys(child)
+		}
+
+		return list
+	}
+
+	return value
+}
@@ -0,0 +1,194 @@
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"runtime/debug"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/phux/apijc/har"
+)
+
+// harTimer collects the timings of a request for its HAR entry.
+type harTimer struct {
+	start        time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+}
+
+// RecordHAR enables recording every request made (to both domains, including
+// sequential targets) and its response into a HAR log, see HAR.
+func (a *App) RecordHAR() {
+	version := "(devel)"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		version = info.Main.Version
+	}
+
+	a.har = &har.HAR{
+		Log: har.Log{
+			Version: "1.2",
+			Creator: har.Creator{Name: "apijc", Version: version},
+			Entries: []har.Entry{},
+		},
+	}
+}
+
+// HAR returns the recorded HAR log, nil if RecordHAR is not enabled.
+func (a *App) HAR() *har.HAR {
+	return a.har
+}
+
+// traceRequest returns req with a trace collecting its timings.
+func traceRequest(req *http.Request) (*http.Request, *harTimer) {
+	timer := &harTimer{start: time.Now()}
+	trace := &httptrace.ClientTrace{
+		WroteRequest:         func(httptrace.WroteRequestInfo) { timer.wroteRequest = time.Now() },
+		GotFirstResponseByte: func() { timer.firstByte = time.Now() },
+	}
+
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), timer
+}
+
+// recordHAREntry adds the exchange of req and res to the HAR log. The response
+// body is read to record it and stays readable. res is nil if the request
+// failed with err. Secret and interpolated values of target are redacted like
+// in the curl commands.
+func (a *App) recordHAREntry(req *http.Request, res *http.Response, err error, timer *harTimer, target Target) {
+	entry := har.Entry{
+		StartedDateTime: timer.start.Format(time.RFC3339Nano),
+		Request:         a.harRequest(req, target),
+		Response:        har.Response{Cookies: []har.Cookie{}, Headers: []har.NameValue{}},
+	}
+
+	if err != nil {
+		entry.Comment = err.Error()
+	}
+
+	if res != nil {
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(body))
+
+		entry.Response = harResponse(res, body)
+	}
+	end := time.Now()
+
+	// the trace hooks are not called if the request failed early or the
+	// transport does not support them, the time is accounted as waiting then
+	if timer.wroteRequest.IsZero() {
+		timer.wroteRequest = timer.start
+	}
+	if timer.firstByte.IsZero() {
+		timer.firstByte = end
+	}
+
+	entry.Timings = har.Timings{
+		Send:    milliseconds(timer.wroteRequest.Sub(timer.start)),
+		Wait:    milliseconds(timer.firstByte.Sub(timer.wroteRequest)),
+		Receive: milliseconds(end.Sub(timer.firstByte)),
+	}
+	entry.Time = entry.Timings.Send + entry.Timings.Wait + entry.Timings.Receive
+
+	a.har.Log.Entries = append(a.har.Log.Entries, entry)
+}
+
+func (a *App) harRequest(req *http.Request, target Target) har.Request {
+	request := har.Request{
+		Method:      req.Method,
+		URL:         a.redactURL(target, req.URL).String(),
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []har.Cookie{},
+		Headers:     []har.NameValue{},
+		QueryString: []har.NameValue{},
+		HeadersSize: -1,
+	}
+
+	for _, header := range harNameValues(req.Header) {
+		if a.redactHeader(target, header.Name) {
+			header.Value = redactHeaderValue(header.Value)
+		}
+		request.Headers = append(request.Headers, header)
+	}
+
+	for _, parameter := range harNameValues(req.URL.Query()) {
+		if !a.showSecrets && (isSecret(parameter.Name) || target.interpolated.query[parameter.Name]) {
+			parameter.Value = redacted
+		}
+		request.QueryString = append(request.QueryString, parameter)
+	}
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			content, _ := io.ReadAll(body)
+			if a.redactBody(target) {
+				content = []byte(redacted)
+			}
+			request.BodySize = len(content)
+			request.PostData = &har.PostData{
+				MimeType: req.Header.Get("Content-Type"),
+				Text:     string(content),
+			}
+		}
+	}
+
+	return request
+}
+
+func harResponse(res *http.Response, body []byte) har.Response {
+	response := har.Response{
+		Status:      res.StatusCode,
+		StatusText:  http.StatusText(res.StatusCode),
+		HTTPVersion: res.Proto,
+		Cookies:     []har.Cookie{},
+		Headers:     []har.NameValue{},
+		Content: har.Content{
+			Size:     len(body),
+			MimeType: res.Header.Get("Content-Type"),
+			Text:     string(body),
+		},
+		RedirectURL: res.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+
+	if !utf8.Valid(body) {
+		response.Content.Text = base64.StdEncoding.EncodeToString(body)
+		response.Content.Encoding = "base64"
+	}
+
+	response.Headers = harNameValues(res.Header)
+
+	return response
+}
+
+// harNameValues returns the values of headers or query parameters, sorted by
+// name.
+func harNameValues(values map[string][]string) []har.NameValue {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nameValues := []har.NameValue{}
+	for _, name := range names {
+		for _, value := range values[name] {
+			nameValues = append(nameValues, har.NameValue{Name: name, Value: value})
+		}
+	}
+
+	return nameValues
+}
+
+func milliseconds(duration time.Duration) float64 {
+	if duration < 0 {
+		return 0
+	}
+
+	return float64(duration.Microseconds()) / 1000
+}
@@ -0,0 +1,100 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/har"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRun_RecordHAR(t *testing.T) {
+	baseDomain := "http://localhost:1234"
+	newDomain := "http://localhost:5678"
+
+	defer gock.Off()
+	gock.New(baseDomain).Post("/foo").Reply(201).SetHeader("Content-Type", "application/json").BodyString(`{"id": 1}`)
+	gock.New(newDomain).Post("/foo").Reply(201).SetHeader("Content-Type", "application/json").BodyString(`{"id": 1}`)
+	gock.New(baseDomain).Get("/bar").Reply(500).BodyString(`boom`)
+
+	a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{
+		Global: app.HeaderKV{"Authorization": "Bearer secret"},
+	})
+	a.RecordHAR()
+	a.AddURLs(app.URLs{
+		Targets: []app.Target{
+			{
+				RelativePath:       "/foo?page=1&api_key=s3cr3t",
+				HTTPMethod:         "POST",
+				ExpectedStatusCode: 201,
+				RequestBody:        stringPointer(`{"name":"foo"}`),
+				RequestHeaders:     map[string]string{"Content-Type": "application/json"},
+			},
+		},
+		SequentialTargets: map[string][]app.Target{
+			"steps": {{RelativePath: "/bar", HTTPMethod: "GET", ExpectedStatusCode: 200}},
+		},
+	})
+
+	assert.NoError(t, a.Run())
+
+	log := a.HAR().Log
+	assert.Equal(t, "1.2", log.Version)
+	assert.Equal(t, "apijc", log.Creator.Name)
+	assert.Len(t, log.Entries, 3)
+
+	base := log.Entries[0]
+	assert.Equal(t, "POST", base.Request.Method)
+	assert.Equal(t, "http://localhost:1234/foo?page=1&api_key=REDACTED", base.Request.URL)
+	assert.Equal(t, []har.NameValue{
+		{Name: "Authorization", Value: "Bearer REDACTED"},
+		{Name: "Content-Type", Value: "application/json"},
+	}, base.Request.Headers)
+	assert.Equal(t, []har.NameValue{
+		{Name: "api_key", Value: "REDACTED"},
+		{Name: "page", Value: "1"},
+	}, base.Request.QueryString)
+	assert.Equal(t, &har.PostData{MimeType: "application/json", Text: `{"name":"foo"}`}, base.Request.PostData)
+	assert.Equal(t, 201, base.Response.Status)
+	assert.Equal(t, har.Content{Size: 9, MimeType: "application/json", Text: `{"id": 1}`}, base.Response.Content)
+	assert.Equal(t, base.Time, base.Timings.Send+base.Timings.Wait+base.Timings.Receive)
+
+	assert.Equal(t, "http://localhost:5678/foo?page=1&api_key=REDACTED", log.Entries[1].Request.URL)
+
+	step := log.Entries[2]
+	assert.Equal(t, "http://localhost:1234/bar", step.Request.URL)
+	assert.Equal(t, 500, step.Response.Status)
+	assert.Equal(t, "boom", step.Response.Content.Text)
+	assert.Nil(t, step.Request.PostData)
+
+	assert.Len(t, a.Results.Findings, 1)
+}
+
+func TestRun_RecordHARShowSecrets(t *testing.T) {
+	baseDomain := "http://localhost:1234"
+	newDomain := "http://localhost:5678"
+
+	defer gock.Off()
+	gock.New(baseDomain).Get("/foo").Reply(200).BodyString(`{}`)
+	gock.New(newDomain).Get("/foo").Reply(200).BodyString(`{}`)
+
+	a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{
+		Global: app.HeaderKV{"Authorization": "Bearer secret"},
+	})
+	a.RecordHAR()
+	a.ShowSecrets()
+	a.AddURLs(app.URLs{
+		Targets: []app.Target{
+			{RelativePath: "/foo?api_key=s3cr3t", HTTPMethod: "GET", ExpectedStatusCode: 200},
+		},
+	})
+
+	assert.NoError(t, a.Run())
+
+	request := a.HAR().Log.Entries[0].Request
+	assert.Equal(t, "http://localhost:1234/foo?api_key=s3cr3t", request.URL)
+	assert.Equal(t, []har.NameValue{{Name: "Authorization", Value: "Bearer secret"}}, request.Headers)
+	assert.Equal(t, []har.NameValue{{Name: "api_key", Value: "s3cr3t"}}, request.QueryString)
+}
@@ -1,5 +1,10 @@
 package app
 
+import (
+	"fmt"
+	"os"
+)
+
 type Headers struct {
 	Global     HeaderKV `json:"global"`
 	BaseDomain HeaderKV `json:"baseDomain"`
@@ -7,3 +12,81 @@ type Headers struct {
 }
 
 type HeaderKV map[string]string
+
+// LoadHeadersFromFile reads a headerFile in JSON, YAML or TOML format.
+func LoadHeadersFromFile(path string) (Headers, error) {
+	var headers Headers
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return headers, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	err = decodeFile(path, content, &headers)
+	if err != nil {
+		return headers, fmt.Errorf("cannot unmarshal %s file: %w", path, err)
+	}
+
+	if err := headers.Interpolate(); err != nil {
+		return headers, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return headers, nil
+}
+
+// Interpolate replaces the variable references (e.g. ${ENV_VAR}) in all
+// header values.
+func (h *Headers) Interpolate() error {
+	return interpolate(h)
+}
+
+// Merge returns the headers of h overridden by the headers of other.
+func (h Headers) Merge(other Headers) Headers {
+	return Headers{
+		Global:     h.Global.merge(other.Global),
+		BaseDomain: h.BaseDomain.merge(other.BaseDomain),
+		NewDomain:  h.NewDomain.merge(other.NewDomain),
+	}
+}
+
+// Redacted returns h with the values of secret headers redacted, like in the
+// curl commands of findings.
+func (h Headers) Redacted() Headers {
+	return Headers{
+		Global:     h.Global.redacted(),
+		BaseDomain: h.BaseDomain.redacted(),
+		NewDomain:  h.NewDomain.redacted(),
+	}
+}
+
+func (kv HeaderKV) redacted() HeaderKV {
+	if kv == nil {
+		return nil
+	}
+
+	redactedKV := make(HeaderKV, len(kv))
+	for key, value := range kv {
+		if isSecret(key) {
+			value = redactHeaderValue(value)
+		}
+		redactedKV[key] = value
+	}
+
+	return redactedKV
+}
+
+func (kv HeaderKV) merge(other HeaderKV) HeaderKV {
+	if kv == nil && other == nil {
+		return nil
+	}
+
+	merged := HeaderKV{}
+	for key, value := range kv {
+		merged[key] = value
+	}
+	for key, value := range other {
+		merged[key] = value
+	}
+
+	return merged
+}
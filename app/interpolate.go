@@ -0,0 +1,256 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var ErrUnresolvedVariables = errors.New("unresolved variables")
+
+// variableReference matches ${ENV_VAR}, ${ENV_VAR:-default} and
+// ${file:/path}. $${...} is an escaped, literal ${...}.
+var variableReference = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+// variableFilePrefix marks references to the content of a file.
+const variableFilePrefix = "file:"
+
+// variablePlaceholder replaces variable references before splitting values,
+// references may contain separators themselves, e.g. ${file:/path}.
+const variablePlaceholder = "\x00"
+
+// interpolator replaces variable references in strings and collects the
+// references that could not be resolved.
+type interpolator struct {
+	unresolved []string
+}
+
+// interpolate replaces the variable references in all string fields of v,
+// which must be a pointer. It returns an error listing all references that
+// could not be resolved.
+func interpolate(v interface{}) error {
+	in := &interpolator{}
+	in.walk(reflect.ValueOf(v), "")
+
+	return in.err()
+}
+
+// interpolateURLs replaces the variable references in all string fields of
+// the targets and in the content of their request body files.
+func interpolateURLs(urls *URLs) error {
+	markInterpolatedValues(urls.Targets)
+	for _, targets := range urls.SequentialTargets {
+		markInterpolatedValues(targets)
+	}
+
+	in := &interpolator{}
+	in.walk(reflect.ValueOf(urls), "")
+
+	in.interpolateBodyFiles(urls.Targets, "targets")
+	for _, name := range sortedSequenceNames(urls.SequentialTargets) {
+		in.interpolateBodyFiles(urls.SequentialTargets[name], joinLocation("sequentialTargets", name))
+	}
+
+	return in.err()
+}
+
+func (in *interpolator) err() error {
+	if len(in.unresolved) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrUnresolvedVariables, strings.Join(in.unresolved, ", "))
+}
+
+// interpolateBodyFiles keeps the interpolated content of the request body
+// files of targets that contain variable references. Missing files are
+// reported when the request is made.
+func (in *interpolator) interpolateBodyFiles(targets []Target, location string) {
+	for i := range targets {
+		if targets[i].RequestBodyFile == nil {
+			continue
+		}
+
+		content, err := os.ReadFile(*targets[i].RequestBodyFile)
+		if err != nil || !variableReference.Match(content) {
+			continue
+		}
+
+		body := in.interpolateString(string(content), fmt.Sprintf("%s[%d].requestBodyFile %s", location, i, *targets[i].RequestBodyFile))
+		targets[i].requestBodyFileContent = &body
+		targets[i].interpolated.body = hasVariables(string(content))
+	}
+}
+
+// markInterpolatedValues marks the request body, headers, query parameters
+// and path segments of the targets that reference variables, before they are
+// replaced.
+func markInterpolatedValues(targets []Target) {
+	for i := range targets {
+		if targets[i].RequestBody != nil && hasVariables(*targets[i].RequestBody) {
+			targets[i].interpolated.body = true
+		}
+
+		headers := map[string]bool{}
+		for name, value := range targets[i].RequestHeaders {
+			if hasVariables(value) {
+				headers[http.CanonicalHeaderKey(name)] = true
+			}
+		}
+
+		path, query, _ := strings.Cut(maskVariables(targets[i].RelativePath), "?")
+
+		segments := map[int]bool{}
+		pathSegments := strings.Split(path, "/")
+		for j, segment := range pathSegments {
+			if strings.Contains(segment, variablePlaceholder) {
+				segments[len(pathSegments)-1-j] = true
+			}
+		}
+
+		parameters := map[string]bool{}
+		for _, parameter := range strings.Split(query, "&") {
+			name, value, _ := strings.Cut(parameter, "=")
+			if strings.Contains(value, variablePlaceholder) {
+				parameters[name] = true
+			}
+		}
+
+		// unmarked targets keep nil maps, so they equal targets that were
+		// never interpolated
+		if len(headers) > 0 {
+			targets[i].interpolated.headers = headers
+		}
+		if len(segments) > 0 {
+			targets[i].interpolated.segments = segments
+		}
+		if len(parameters) > 0 {
+			targets[i].interpolated.query = parameters
+		}
+	}
+}
+
+// hasVariables reports whether value references variables, not counting
+// escaped references.
+func hasVariables(value string) bool {
+	return strings.Contains(maskVariables(value), variablePlaceholder)
+}
+
+// maskVariables replaces the variable references in value with
+// variablePlaceholder, escaped references are kept.
+func maskVariables(value string) string {
+	return variableReference.ReplaceAllStringFunc(value, func(reference string) string {
+		if strings.HasPrefix(reference, "$$") {
+			return reference
+		}
+
+		return variablePlaceholder
+	})
+}
+
+func (in *interpolator) walk(value reflect.Value, location string) {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return
+		}
+
+		if !value.CanSet() {
+			in.walk(value.Elem(), location)
+
+			return
+		}
+
+		// copy the value, the pointer may be shared
+		copied := reflect.New(value.Elem().Type())
+		copied.Elem().Set(value.Elem())
+		in.walk(copied.Elem(), location)
+		value.Set(copied)
+	case reflect.String:
+		if value.CanSet() {
+			value.SetString(in.interpolateString(value.String(), location))
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" {
+				name = field.Name
+			}
+			in.walk(value.Field(i), joinLocation(location, name))
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			in.walk(value.Index(i), fmt.Sprintf("%s[%d]", location, i))
+		}
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		for _, key := range keys {
+			element := reflect.New(value.Type().Elem()).Elem()
+			element.Set(value.MapIndex(key))
+			in.walk(element, joinLocation(location, key.String()))
+			value.SetMapIndex(key, element)
+		}
+	}
+}
+
+func (in *interpolator) interpolateString(value, location string) string {
+	return variableReference.ReplaceAllStringFunc(value, func(reference string) string {
+		if strings.HasPrefix(reference, "$$") {
+			return reference[1:]
+		}
+
+		name := reference[2 : len(reference)-1]
+		resolved, ok := resolveVariable(name)
+		if !ok {
+			in.unresolved = append(in.unresolved, fmt.Sprintf("%s (%s)", reference, location))
+
+			return reference
+		}
+
+		return resolved
+	})
+}
+
+// resolveVariable returns the value of an environment variable (with an
+// optional default after :-) or the content of a file.
+func resolveVariable(name string) (string, bool) {
+	if path, ok := strings.CutPrefix(name, variableFilePrefix); ok {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", false
+		}
+
+		return strings.TrimRight(string(content), "\r\n"), true
+	}
+
+	name, defaultValue, hasDefault := strings.Cut(name, ":-")
+	if name == "" {
+		return "", false
+	}
+
+	if value, ok := os.LookupEnv(name); ok && (value != "" || !hasDefault) {
+		return value, true
+	}
+
+	return defaultValue, hasDefault
+}
+
+func joinLocation(location, name string) string {
+	if location == "" {
+		return name
+	}
+
+	return location + "." + name
+}
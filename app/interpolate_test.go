@@ -0,0 +1,255 @@
+package app_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/har"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestLoadURLsFromFile_Interpolation(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "token")
+	bodyFile := filepath.Join(dir, "body.json")
+	urlFile := filepath.Join(dir, "urlfile.yaml")
+
+	t.Setenv("APIJC_TEST_USER", "42")
+	assert.NoError(t, os.WriteFile(secretFile, []byte("s3cr3t\n"), 0o644))
+	assert.NoError(t, os.WriteFile(bodyFile, []byte(`{"user":"${APIJC_TEST_USER}"}`), 0o644))
+	assert.NoError(t, os.WriteFile(urlFile, []byte(`
+targets:
+  - relativePath: /users/${APIJC_TEST_USER}?page={1-2}
+    httpMethod: GET
+    expectedStatusCode: 200
+    requestHeaders:
+      Authorization: Bearer ${file:`+secretFile+`}
+      X-Literal: $${NOT_A_VARIABLE}
+      X-Region: ${APIJC_TEST_REGION:-eu}
+  - relativePath: /users
+    httpMethod: POST
+    expectedStatusCode: 201
+    requestBodyFile: `+bodyFile+`
+`), 0o644))
+
+	urls, err := app.LoadURLsFromFile(urlFile)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/users/42?page={1-2}", urls.Targets[0].RelativePath)
+	assert.Equal(t, map[string]string{
+		"Authorization": "Bearer s3cr3t",
+		"X-Literal":     "${NOT_A_VARIABLE}",
+		"X-Region":      "eu",
+	}, urls.Targets[0].RequestHeaders)
+
+	baseDomain := "http://localhost:1234"
+	newDomain := "http://localhost:5678"
+
+	defer gock.Off()
+	gock.New(baseDomain).Post("/users").BodyString(`{"user":"42"}`).Reply(201).BodyString(`{}`)
+	gock.New(newDomain).Post("/users").BodyString(`{"user":"42"}`).Reply(201).BodyString(`{}`)
+
+	a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+	_, _, err = a.CheckTarget(urls.Targets[1])
+	assert.NoError(t, err)
+	assert.Empty(t, a.Results.Findings)
+}
+
+func TestLoadURLsFromFile_UnresolvedVariables(t *testing.T) {
+	dir := t.TempDir()
+	urlFile := filepath.Join(dir, "urlfile.json")
+
+	assert.NoError(t, os.WriteFile(urlFile, []byte(`{
+		"targets": [{
+			"relativePath": "/users/${APIJC_TEST_MISSING}",
+			"httpMethod": "GET",
+			"expectedStatusCode": 200,
+			"requestHeaders": {"Authorization": "${file:/does/not/exist}"}
+		}]
+	}`), 0o644))
+
+	_, err := app.LoadURLsFromFile(urlFile)
+
+	assert.ErrorIs(t, err, app.ErrUnresolvedVariables)
+	assert.EqualError(t, err, urlFile+": unresolved variables: "+
+		"${APIJC_TEST_MISSING} (targets[0].relativePath), "+
+		"${file:/does/not/exist} (targets[0].requestHeaders.Authorization)")
+}
+
+func TestCheckTarget_RedactsInterpolatedBodies(t *testing.T) {
+	dir := t.TempDir()
+	bodyFile := filepath.Join(dir, "body.json")
+	urlFile := filepath.Join(dir, "urlfile.yaml")
+
+	t.Setenv("APIJC_TEST_PASSWORD", "s3cr3t")
+	assert.NoError(t, os.WriteFile(bodyFile, []byte(`{"password":"${APIJC_TEST_PASSWORD}"}`), 0o644))
+	assert.NoError(t, os.WriteFile(urlFile, []byte(`
+targets:
+  - relativePath: /inline
+    httpMethod: POST
+    expectedStatusCode: 200
+    requestBody: '{"password":"${APIJC_TEST_PASSWORD}"}'
+  - relativePath: /file
+    httpMethod: POST
+    expectedStatusCode: 200
+    requestBodyFile: `+bodyFile+`
+`), 0o644))
+
+	urls, err := app.LoadURLsFromFile(urlFile)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		showSecrets     bool
+		target          app.Target
+		expectedBaseCmd string
+		expectedBody    string
+	}{
+		{
+			name:            "inline body is redacted",
+			target:          urls.Targets[0],
+			expectedBaseCmd: `curl -X POST 'http://localhost:1234/inline' --data-raw 'REDACTED'`,
+			expectedBody:    "REDACTED",
+		},
+		{
+			name:            "body file is referenced and redacted",
+			target:          urls.Targets[1],
+			expectedBaseCmd: `curl -X POST 'http://localhost:1234/file' --data-binary '@` + bodyFile + `'`,
+			expectedBody:    "REDACTED",
+		},
+		{
+			name:            "inline body is shown",
+			showSecrets:     true,
+			target:          urls.Targets[0],
+			expectedBaseCmd: `curl -X POST 'http://localhost:1234/inline' --data-raw '{"password":"s3cr3t"}'`,
+			expectedBody:    `{"password":"s3cr3t"}`,
+		},
+		{
+			name:            "body file content is shown",
+			showSecrets:     true,
+			target:          urls.Targets[1],
+			expectedBaseCmd: `curl -X POST 'http://localhost:1234/file' --data-raw '{"password":"s3cr3t"}'`,
+			expectedBody:    `{"password":"s3cr3t"}`,
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			baseDomain := "http://localhost:1234"
+			newDomain := "http://localhost:5678"
+
+			defer gock.Off()
+			gock.New(baseDomain).Post(tt.target.RelativePath).Reply(200).BodyString(`{"id": 1}`)
+			gock.New(newDomain).Post(tt.target.RelativePath).Reply(200).BodyString(`{"id": 2}`)
+
+			a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+			a.WriteArtifacts(t.TempDir())
+			a.RecordHAR()
+			if tt.showSecrets {
+				a.ShowSecrets()
+			}
+
+			_, _, err := a.CheckTarget(tt.target)
+			assert.NoError(t, err)
+
+			assert.Len(t, a.Results.Findings, 1)
+			assert.Equal(t, tt.expectedBaseCmd, a.Results.Findings[0].BaseCurl)
+
+			request, err := os.ReadFile(a.Results.Findings[0].Artifacts.BaseRequest)
+			assert.NoError(t, err)
+			assert.Contains(t, string(request), "\r\n\r\n"+tt.expectedBody)
+
+			assert.Equal(t, tt.expectedBody, a.HAR().Log.Entries[0].Request.PostData.Text)
+		})
+	}
+}
+
+func TestCheckTarget_RedactsInterpolatedHeadersAndPaths(t *testing.T) {
+	urlFile := filepath.Join(t.TempDir(), "urlfile.yaml")
+
+	t.Setenv("APIJC_TEST_USER", "42")
+	t.Setenv("APIJC_TEST_TOKEN", "s3cr3t")
+	assert.NoError(t, os.WriteFile(urlFile, []byte(`
+targets:
+  - relativePath: /users/${APIJC_TEST_USER}/orders?k2=${APIJC_TEST_TOKEN}&page=1
+    httpMethod: GET
+    expectedStatusCode: 200
+    requestHeaders:
+      X-Api: ${APIJC_TEST_TOKEN}
+      X-Plain: plain
+`), 0o644))
+
+	urls, err := app.LoadURLsFromFile(urlFile)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		showSecrets     bool
+		expectedBaseCmd string
+		expectedRequest string
+		expectedHAR     har.Request
+	}{
+		{
+			name:            "interpolated values are redacted",
+			expectedBaseCmd: `curl -X GET 'http://localhost:1234/users/REDACTED/orders?k2=REDACTED&page=1' -H 'X-Api: REDACTED' -H 'X-Plain: plain'`,
+			expectedRequest: "GET /users/REDACTED/orders?k2=REDACTED&page=1 HTTP/1.1\r\n",
+			expectedHAR: har.Request{
+				URL:         "http://localhost:1234/users/REDACTED/orders?k2=REDACTED&page=1",
+				Headers:     []har.NameValue{{Name: "X-Api", Value: "REDACTED"}, {Name: "X-Plain", Value: "plain"}},
+				QueryString: []har.NameValue{{Name: "k2", Value: "REDACTED"}, {Name: "page", Value: "1"}},
+			},
+		},
+		{
+			name:            "interpolated values are shown",
+			showSecrets:     true,
+			expectedBaseCmd: `curl -X GET 'http://localhost:1234/users/42/orders?k2=s3cr3t&page=1' -H 'X-Api: s3cr3t' -H 'X-Plain: plain'`,
+			expectedRequest: "GET /users/42/orders?k2=s3cr3t&page=1 HTTP/1.1\r\n",
+			expectedHAR: har.Request{
+				URL:         "http://localhost:1234/users/42/orders?k2=s3cr3t&page=1",
+				Headers:     []har.NameValue{{Name: "X-Api", Value: "s3cr3t"}, {Name: "X-Plain", Value: "plain"}},
+				QueryString: []har.NameValue{{Name: "k2", Value: "s3cr3t"}, {Name: "page", Value: "1"}},
+			},
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			baseDomain := "http://localhost:1234"
+			newDomain := "http://localhost:5678"
+
+			defer gock.Off()
+			gock.New(baseDomain).Get("/users/42/orders").Reply(200).BodyString(`{"id": 1}`)
+			gock.New(newDomain).Get("/users/42/orders").Reply(200).BodyString(`{"id": 2}`)
+
+			a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+			a.WriteArtifacts(t.TempDir())
+			a.RecordHAR()
+			if tt.showSecrets {
+				a.ShowSecrets()
+			}
+
+			_, _, err := a.CheckTarget(urls.Targets[0])
+			assert.NoError(t, err)
+
+			assert.Len(t, a.Results.Findings, 1)
+			assert.Equal(t, tt.expectedBaseCmd, a.Results.Findings[0].BaseCurl)
+
+			request, err := os.ReadFile(a.Results.Findings[0].Artifacts.BaseRequest)
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(string(request), tt.expectedRequest), string(request))
+			assert.Equal(t, !tt.showSecrets, !strings.Contains(string(request), "s3cr3t"))
+
+			recorded := a.HAR().Log.Entries[0].Request
+			assert.Equal(t, tt.expectedHAR.URL, recorded.URL)
+			assert.Equal(t, tt.expectedHAR.QueryString, recorded.QueryString)
+			assert.Subset(t, recorded.Headers, tt.expectedHAR.Headers)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidJSONPath = errors.New("invalid JSON path, must be a JSON pointer like /foo/0/bar")
+
+// jsonPathWildcard matches every key of an object or every index of an array.
+const jsonPathWildcard = "*"
+
+// jsonPath is a parsed JSON pointer (RFC 6901). Segments may be "*" to match
+// all children of an object or array.
+type jsonPath []string
+
+func parseJSONPath(raw string) (jsonPath, error) {
+	if raw == "" {
+		return jsonPath{}, nil
+	}
+
+	if !strings.HasPrefix(raw, "/") {
+		return nil, fmt.Errorf("%q: %w", raw, ErrInvalidJSONPath)
+	}
+
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	segments := strings.Split(raw[1:], "/")
+	for i, segment := range segments {
+		segments[i] = unescape.Replace(segment)
+	}
+
+	return segments, nil
+}
+
+func (p jsonPath) hasWildcard() bool {
+	for _, segment := range p {
+		if segment == jsonPathWildcard {
+			return true
+		}
+	}
+
+	return false
+}
+
+// modifyPath calls fn for every value in node matching path and replaces the
+// value with fn's result. If fn returns keep == false, the value is removed
+// from its parent. Paths that do not exist in node are skipped.
+func modifyPath(
+	node interface{},
+	path jsonPath,
+	fn func(value interface{}) (newValue interface{}, keep bool, err error),
+) (interface{}, bool, error) {
+	if len(path) == 0 {
+		return fn(node)
+	}
+
+	segment, rest := path[0], path[1:]
+
+	switch typed := node.(type) {
+	case map[string]interface{}:
+		keys := []string{segment}
+		if segment == jsonPathWildcard {
+			keys = make([]string, 0, len(typed))
+			for key := range typed {
+				keys = append(keys, key)
+			}
+		}
+
+		for _, key := range keys {
+			child, ok := typed[key]
+			if !ok {
+				continue
+			}
+
+			newChild, keep, err := modifyPath(child, rest, fn)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if keep {
+				typed[key] = newChild
+			} else {
+				delete(typed, key)
+			}
+		}
+
+		return typed, true, nil
+	case []interface{}:
+		result := make([]interface{}, 0, len(typed))
+		for i, child := range typed {
+			if segment != jsonPathWildcard && segment != strconv.Itoa(i) {
+				result = append(result, child)
+
+				continue
+			}
+
+			newChild, keep, err := modifyPath(child, rest, fn)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if keep {
+				result = append(result, newChild)
+			}
+		}
+
+		return result, true, nil
+	}
+
+	return node, true, nil
+}
+
+// setPath sets value at path in node, creating missing objects on the way.
+func setPath(node interface{}, path jsonPath, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	segment, rest := path[0], path[1:]
+
+	switch typed := node.(type) {
+	case map[string]interface{}:
+		child, err := setPath(typed[segment], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		typed[segment] = child
+
+		return typed, nil
+	case []interface{}:
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 || index > len(typed) {
+			return nil, fmt.Errorf("%q: array index out of range: %w", segment, ErrInvalidJSONPath)
+		}
+
+		if index == len(typed) {
+			typed = append(typed, nil)
+		}
+
+		child, err := setPath(typed[index], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		typed[index] = child
+
+		return typed, nil
+	case nil:
+		return setPath(map[string]interface{}{}, path, value)
+	}
+
+	return nil, fmt.Errorf("%q: cannot set a child of a scalar value: %w", segment, ErrInvalidJSONPath)
+}
+
+// resolve returns the concrete paths (without wildcards) in doc matching p.
+func (p jsonPath) resolve(doc interface{}) []jsonPath {
+	if len(p) == 0 {
+		return []jsonPath{{}}
+	}
+
+	segment, rest := p[0], p[1:]
+
+	var keys []string
+	switch typed := doc.(type) {
+	case map[string]interface{}:
+		for key := range typed {
+			if segment == jsonPathWildcard || segment == key {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+	case []interface{}:
+		for i := range typed {
+			if segment == jsonPathWildcard || segment == strconv.Itoa(i) {
+				keys = append(keys, strconv.Itoa(i))
+			}
+		}
+	}
+
+	paths := []jsonPath{}
+	for _, key := range keys {
+		child, _ := getPath(doc, jsonPath{key})
+		for _, childPath := range rest.resolve(child) {
+			paths = append(paths, append(jsonPath{key}, childPath...))
+		}
+	}
+
+	return paths
+}
+
+// getPath returns the value at the concrete path in doc.
+func getPath(doc interface{}, path jsonPath) (interface{}, bool) {
+	for _, segment := range path {
+		switch typed := doc.(type) {
+		case map[string]interface{}:
+			child, ok := typed[segment]
+			if !ok {
+				return nil, false
+			}
+			doc = child
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(typed) {
+				return nil, false
+			}
+			doc = typed[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return doc, true
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"sort"
+	"strings"
+)
+
+// domainPlaceholder replaces the domains in URLs embedded in response bodies.
+const domainPlaceholder = "{domain}"
+
+// urlNormalizer rewrites absolute URLs embedded in string values of response
+// bodies, so links to the serving domain do not differ between base and new.
+type urlNormalizer struct {
+	domains []string
+}
+
+func newURLNormalizer(domains ...string) *urlNormalizer {
+	normalizer := &urlNormalizer{}
+	for _, domain := range domains {
+		domain = strings.TrimRight(domain, "/")
+		if domain != "" {
+			normalizer.domains = append(normalizer.domains, domain)
+		}
+	}
+
+	// replace longer domains first, so a domain that is a prefix of another
+	// one does not leave parts of the longer domain behind
+	sort.SliceStable(normalizer.domains, func(i, j int) bool {
+		return len(normalizer.domains[i]) > len(normalizer.domains[j])
+	})
+
+	return normalizer
+}
+
+func (n *urlNormalizer) normalize(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			typed[key] = n.normalize(child)
+		}
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = n.normalize(child)
+		}
+	case string:
+		return n.normalizeString(typed)
+	}
+
+	return value
+}
+
+func (n *urlNormalizer) normalizeString(value string) string {
+	replaced := false
+	for _, domain := range n.domains {
+		var replacedDomain bool
+		value, replacedDomain = replaceDomain(value, domain)
+		replaced = replaced || replacedDomain
+	}
+
+	if !replaced || !strings.HasPrefix(value, domainPlaceholder) {
+		return value
+	}
+
+	return sortQueryParameters(value)
+}
+
+// replaceDomain replaces the occurrences of domain in value that end the value
+// or are followed by a path, query or fragment. Other occurrences are part of
+// a different host or port, e.g. http://localhost:1234 in
+// http://localhost:12345, and are kept.
+func replaceDomain(value, domain string) (string, bool) {
+	var result strings.Builder
+	replaced := false
+
+	for {
+		i := strings.Index(value, domain)
+		if i < 0 {
+			break
+		}
+
+		end := i + len(domain)
+		result.WriteString(value[:i])
+		if end == len(value) || strings.ContainsRune("/?#", rune(value[end])) {
+			result.WriteString(domainPlaceholder)
+			replaced = true
+		} else {
+			result.WriteString(domain)
+		}
+		value = value[end:]
+	}
+	result.WriteString(value)
+
+	return result.String(), replaced
+}
+
+// sortQueryParameters sorts the query parameters of rawURL, so that their
+// order does not matter when comparing URLs.
+func sortQueryParameters(rawURL string) string {
+	fragment := ""
+	if i := strings.Index(rawURL, "#"); i >= 0 {
+		rawURL, fragment = rawURL[:i], rawURL[i:]
+	}
+
+	i := strings.Index(rawURL, "?")
+	if i < 0 {
+		return rawURL + fragment
+	}
+
+	parameters := strings.Split(rawURL[i+1:], "&")
+	sort.Strings(parameters)
+
+	return rawURL[:i+1] + strings.Join(parameters, "&") + fragment
+}
@@ -0,0 +1,116 @@
+package app_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCheckTarget_WithNormalizedURLs(t *testing.T) {
+	tests := []struct {
+		name         string
+		normalize    bool
+		aliases      []string
+		baseBody     string
+		newBody      string
+		expectedDiff string
+	}{
+		{
+			name:         "embedded domains differ without normalization",
+			baseBody:     `{"self": "http://localhost:1234/foo"}`,
+			newBody:      `{"self": "http://localhost:5678/foo"}`,
+			expectedDiff: "@ [\"self\"]\n- \"http://localhost:1234/foo\"\n+ \"http://localhost:5678/foo\"\n",
+		},
+		{
+			name:      "embedded domains are normalized",
+			normalize: true,
+			baseBody:  `{"_links": {"self": {"href": "http://localhost:1234/foo"}}, "items": ["see http://localhost:1234/bar"]}`,
+			newBody:   `{"_links": {"self": {"href": "http://localhost:5678/foo"}}, "items": ["see http://localhost:5678/bar"]}`,
+		},
+		{
+			name:      "aliases are normalized",
+			normalize: true,
+			aliases:   []string{"https://public.example.com"},
+			baseBody:  `{"next": "https://public.example.com/foo?page=2"}`,
+			newBody:   `{"next": "http://localhost:5678/foo?page=2"}`,
+		},
+		{
+			name:      "query parameter order is ignored",
+			normalize: true,
+			baseBody:  `{"next": "http://localhost:1234/foo?page=2&size=10#top"}`,
+			newBody:   `{"next": "http://localhost:5678/foo?size=10&page=2#top"}`,
+		},
+		{
+			name:         "other ports are not normalized",
+			normalize:    true,
+			baseBody:     `{"next": "http://localhost:12345/x", "self": "http://localhost:1234"}`,
+			newBody:      `{"next": "http://localhost:56789/x", "self": "http://localhost:5678"}`,
+			expectedDiff: "@ [\"next\"]\n- \"http://localhost:12345/x\"\n+ \"http://localhost:56789/x\"\n",
+		},
+		{
+			name:         "paths still differ",
+			normalize:    true,
+			baseBody:     `{"next": "http://localhost:1234/foo"}`,
+			newBody:      `{"next": "http://localhost:5678/bar"}`,
+			expectedDiff: "@ [\"next\"]\n- \"{domain}/foo\"\n+ \"{domain}/bar\"\n",
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			baseDomain := "http://localhost:1234"
+			newDomain := "http://localhost:5678"
+
+			defer gock.Off()
+			gock.New(baseDomain).Get("/foo").Reply(200).BodyString(tt.baseBody)
+			gock.New(newDomain).Get("/foo").Reply(200).BodyString(tt.newBody)
+
+			a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+			if tt.normalize {
+				a.NormalizeURLs(tt.aliases...)
+			}
+			_, _, err := a.CheckTarget(app.Target{
+				RelativePath:       "/foo",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+			})
+			assert.NoError(t, err)
+
+			if tt.expectedDiff == "" {
+				assert.Empty(t, a.Results.Findings)
+			} else {
+				assert.Len(t, a.Results.Findings, 1)
+				assert.Equal(t, tt.expectedDiff, a.Results.Findings[0].Diff)
+			}
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
+
+func TestCheckTarget_LogsSkippedPreparationOfInvalidJSON(t *testing.T) {
+	baseDomain := "http://localhost:1234"
+	newDomain := "http://localhost:5678"
+
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	defer gock.Off()
+	gock.New(baseDomain).Get("/foo").Reply(200).BodyString(`see http://localhost:1234/foo`)
+	gock.New(newDomain).Get("/foo").Reply(200).BodyString(`see http://localhost:5678/foo`)
+
+	a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+	a.NormalizeURLs()
+	_, _, err := a.CheckTarget(app.Target{RelativePath: "/foo", HTTPMethod: "GET", ExpectedStatusCode: 200})
+	assert.NoError(t, err)
+
+	assert.Len(t, a.Results.Findings, 1)
+	assert.Contains(t, logged.String(), "GET /foo: response is not valid JSON, comparing it as it is")
+}
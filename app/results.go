@@ -1,11 +1,42 @@
 package app
 
+const (
+	// DomainBase marks findings about the response of BaseDomain only.
+	DomainBase = "base"
+	// DomainNew marks findings about the response of NewDomain only.
+	DomainNew = "new"
+)
+
 type Results struct {
 	Findings []Finding
 }
 
 type Finding struct {
-	URL   string `json:"url"`
-	Error string `json:"error"`
-	Diff  string `json:"diff"`
+	URL            string       `json:"url"`
+	Domain         string       `json:"domain,omitempty"`
+	HTTPMethod     string       `json:"httpMethod,omitempty"`
+	BaseURL        string       `json:"baseUrl,omitempty"`
+	NewURL         string       `json:"newUrl,omitempty"`
+	BaseCurl       string       `json:"baseCurl,omitempty"`
+	NewCurl        string       `json:"newCurl,omitempty"`
+	BaseStatusCode int          `json:"baseStatusCode,omitempty"`
+	NewStatusCode  int          `json:"newStatusCode,omitempty"`
+	Error          string       `json:"error"`
+	Severity       string       `json:"severity"`
+	Diff           string       `json:"diff"`
+	Differences    []Difference `json:"differences,omitempty"`
+	Violations     []Violation  `json:"violations,omitempty"`
+	Artifacts      *Artifacts   `json:"artifacts,omitempty"`
+}
+
+// Breaking returns all findings with severity breaking.
+func (r *Results) Breaking() []Finding {
+	breaking := []Finding{}
+	for _, finding := range r.Findings {
+		if finding.Severity == SeverityBreaking {
+			breaking = append(breaking, finding)
+		}
+	}
+
+	return breaking
 }
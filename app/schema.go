@@ -0,0 +1,117 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+var (
+	ErrSchemaViolation       = errors.New("response does not match responseSchema")
+	ErrResponseSchemaInvalid = errors.New("could not load responseSchema")
+)
+
+// Violation is a single failed JSON Schema validation of a response body.
+type Violation struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// loadSchema compiles the JSON Schema at path. Compiled schemas are cached,
+// so each file is only compiled once per run.
+func (a *App) loadSchema(path string) (*jsonschema.Schema, error) {
+	if schema, ok := a.schemas[path]; ok {
+		return schema, nil
+	}
+
+	schema, err := jsonschema.Compile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", path, ErrResponseSchemaInvalid, err)
+	}
+
+	a.schemas[path] = schema
+
+	return schema, nil
+}
+
+// validateResponseBodies adds a finding for each response body that does
+// not match the JSON Schema of target.
+func (a *App) validateResponseBodies(target Target, finding Finding, baseBodyJSON, newBodyJSON []byte) error {
+	schema, err := a.loadSchema(*target.ResponseSchema)
+	if err != nil {
+		return err
+	}
+
+	a.addViolations(target, finding, DomainBase, validateResponseBody(schema, baseBodyJSON), ErrSchemaViolation)
+	a.addViolations(target, finding, DomainNew, validateResponseBody(schema, newBodyJSON), ErrSchemaViolation)
+
+	return nil
+}
+
+// addViolations adds a finding for the response of domain, if there are
+// violations.
+func (a *App) addViolations(target Target, finding Finding, domain string, violations []Violation, err error) {
+	if len(violations) == 0 {
+		return
+	}
+
+	finding.Domain = domain
+	finding.URL = finding.BaseURL
+	if domain == DomainNew {
+		finding.URL = finding.NewURL
+	}
+	finding.Violations = violations
+
+	a.addFinding(target, finding, err)
+}
+
+// validateResponseBody validates body against schema and returns all
+// violations, ordered by their JSON pointer.
+func validateResponseBody(schema *jsonschema.Schema, body []byte) []Violation {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return []Violation{{Pointer: "", Message: fmt.Sprintf("invalid JSON: %s", err)}}
+	}
+
+	err := schema.Validate(doc)
+
+	var validationError *jsonschema.ValidationError
+	if !errors.As(err, &validationError) {
+		if err != nil {
+			return []Violation{{Pointer: "", Message: err.Error()}}
+		}
+
+		return nil
+	}
+
+	violations := leafViolations(validationError)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Pointer < violations[j].Pointer
+	})
+
+	return violations
+}
+
+// leafViolations returns the most specific causes of a validation error.
+func leafViolations(validationError *jsonschema.ValidationError) []Violation {
+	if len(validationError.Causes) == 0 {
+		return []Violation{{
+			Pointer: validationError.InstanceLocation,
+			Message: validationError.Message,
+		}}
+	}
+
+	violations := []Violation{}
+	for _, cause := range validationError.Causes {
+		violations = append(violations, leafViolations(cause)...)
+	}
+
+	return violations
+}
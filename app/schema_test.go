@@ -0,0 +1,116 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCheckTarget_WithResponseSchema(t *testing.T) {
+	tests := []struct {
+		name             string
+		responseSchema   string
+		baseBody         string
+		newBody          string
+		expectedFindings []app.Finding
+	}{
+		{
+			name:           "both responses are valid",
+			responseSchema: "../.testdata/response_schema.json",
+			baseBody:       `{"id": 1, "items": [{"name": "a"}]}`,
+			newBody:        `{"id": 1, "items": [{"name": "a"}]}`,
+		},
+		{
+			name:           "both responses are equally invalid",
+			responseSchema: "../.testdata/response_schema.json",
+			baseBody:       `{"id": "1", "items": [{"name": 1}]}`,
+			newBody:        `{"id": "1", "items": [{"name": 1}]}`,
+			expectedFindings: []app.Finding{
+				{
+					URL:   "http://localhost:1234/foo",
+					Error: app.ErrSchemaViolation.Error(),
+					Violations: []app.Violation{
+						{Pointer: "/id", Message: "expected integer, but got string"},
+						{Pointer: "/items/0/name", Message: "expected string, but got number"},
+					},
+				},
+				{
+					URL:   "http://localhost:5678/foo",
+					Error: app.ErrSchemaViolation.Error(),
+					Violations: []app.Violation{
+						{Pointer: "/id", Message: "expected integer, but got string"},
+						{Pointer: "/items/0/name", Message: "expected string, but got number"},
+					},
+				},
+			},
+		},
+		{
+			name:           "only new response is invalid",
+			responseSchema: "../.testdata/response_schema.json",
+			baseBody:       `{"id": 1, "items": []}`,
+			newBody:        `{"id": 1}`,
+			expectedFindings: []app.Finding{
+				{
+					URL:   "http://localhost:5678/foo",
+					Error: app.ErrSchemaViolation.Error(),
+					Violations: []app.Violation{
+						{Pointer: "", Message: "missing properties: 'items'"},
+					},
+				},
+				{
+					URL:   "/foo",
+					Error: app.ErrJSONMismatch.Error(),
+				},
+			},
+		},
+		{
+			name:           "missing schema file does not skip the comparison",
+			responseSchema: "../.testdata/does_not_exist.json",
+			baseBody:       `{"id": 1}`,
+			newBody:        `{"id": 2}`,
+			expectedFindings: []app.Finding{
+				{
+					URL: "/foo",
+				},
+				{
+					URL:   "/foo",
+					Error: app.ErrJSONMismatch.Error(),
+				},
+			},
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			baseDomain := "http://localhost:1234"
+			newDomain := "http://localhost:5678"
+
+			defer gock.Off()
+			gock.New(baseDomain).Get("/foo").Reply(200).BodyString(tt.baseBody)
+			gock.New(newDomain).Get("/foo").Reply(200).BodyString(tt.newBody)
+
+			a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+			_, _, err := a.CheckTarget(app.Target{
+				RelativePath:       "/foo",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+				ResponseSchema:     stringPointer(tt.responseSchema),
+			})
+			assert.NoError(t, err)
+
+			assert.Len(t, a.Results.Findings, len(tt.expectedFindings))
+			for i, expected := range tt.expectedFindings {
+				assert.Equal(t, expected.URL, a.Results.Findings[i].URL)
+				if expected.Error != "" {
+					assert.Equal(t, expected.Error, a.Results.Findings[i].Error)
+				}
+				assert.Equal(t, expected.Violations, a.Results.Findings[i].Violations)
+			}
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package app
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	jd "github.com/josephburnett/jd/lib"
+)
+
+// CompareModeShape compares only the structure and the value types of the
+// responses and ignores the values themselves.
+const CompareModeShape = "shape"
+
+// unionShape is the shape of a value merged from the elements of an array.
+// The flags are only known within arrays and removed by alignShapes.
+type unionShape struct {
+	shape interface{}
+	// optional is set for fields that some elements lack.
+	optional bool
+	// nullable is set if some elements are null.
+	nullable bool
+}
+
+// shapeOf replaces every scalar value in doc with the name of its JSON type.
+// Arrays are reduced to the union of the shapes of their elements (see
+// mergeShapes), so arrays of different lengths have the same shape.
+func shapeOf(doc interface{}) interface{} {
+	switch typed := doc.(type) {
+	case map[string]interface{}:
+		shape := make(map[string]interface{}, len(typed))
+		for key, child := range typed {
+			shape[key] = shapeOf(child)
+		}
+
+		return shape
+	case []interface{}:
+		if len(typed) == 0 {
+			return []interface{}{}
+		}
+
+		union := shapeOf(typed[0])
+		for _, child := range typed[1:] {
+			union = mergeShapes(union, shapeOf(child))
+		}
+
+		return []interface{}{union}
+	}
+
+	return jsonType(doc)
+}
+
+// mergeShapes returns the union of two element shapes: fields missing in one
+// of them are optional, null makes the other shape nullable and different
+// scalar types are joined, e.g. number|string.
+func mergeShapes(a, b interface{}) interface{} {
+	first, second := asUnion(a), asUnion(b)
+	merged := unionShape{
+		optional: first.optional || second.optional,
+		nullable: first.nullable || second.nullable,
+	}
+
+	firstMap, firstIsMap := first.shape.(map[string]interface{})
+	secondMap, secondIsMap := second.shape.(map[string]interface{})
+	firstArray, firstIsArray := first.shape.([]interface{})
+	secondArray, secondIsArray := second.shape.([]interface{})
+
+	switch {
+	case first.shape == jsonTypeNull:
+		merged.shape, merged.nullable = second.shape, true
+	case second.shape == jsonTypeNull:
+		merged.shape, merged.nullable = first.shape, true
+	case firstIsMap && secondIsMap:
+		shape := make(map[string]interface{}, len(firstMap))
+		for key, child := range firstMap {
+			if other, ok := secondMap[key]; ok {
+				shape[key] = mergeShapes(child, other)
+
+				continue
+			}
+			optionalChild := asUnion(child)
+			optionalChild.optional = true
+			shape[key] = optionalChild
+		}
+		for key, child := range secondMap {
+			if _, ok := firstMap[key]; !ok {
+				optionalChild := asUnion(child)
+				optionalChild.optional = true
+				shape[key] = optionalChild
+			}
+		}
+		merged.shape = shape
+	case firstIsArray && secondIsArray:
+		switch {
+		case len(firstArray) == 0:
+			merged.shape = secondArray
+		case len(secondArray) == 0:
+			merged.shape = firstArray
+		default:
+			merged.shape = []interface{}{mergeShapes(firstArray[0], secondArray[0])}
+		}
+	default:
+		merged.shape = unionTypeName(first.shape, second.shape)
+	}
+
+	if !merged.optional && !merged.nullable {
+		return merged.shape
+	}
+
+	return merged
+}
+
+func asUnion(shape interface{}) unionShape {
+	if union, ok := shape.(unionShape); ok {
+		return union
+	}
+
+	return unionShape{shape: shape}
+}
+
+// unionTypeName joins the type names of two different shapes, e.g.
+// number|string.
+func unionTypeName(a, b interface{}) string {
+	names := map[string]bool{}
+	for _, shape := range []interface{}{a, b} {
+		name, ok := shape.(string)
+		if !ok {
+			name = jsonType(shape)
+		}
+		for _, part := range strings.Split(name, "|") {
+			names[part] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, "|")
+}
+
+// alignShapes removes the differences between two shapes that are only due
+// to the elements sampled in arrays: optional fields missing in the other
+// shape, nullable values that are null in the other shape and empty arrays,
+// which match arrays of any shape. The returned shapes are plain JSON values.
+func alignShapes(baseShape, newShape interface{}) (interface{}, interface{}) {
+	base, next := asUnion(baseShape), asUnion(newShape)
+
+	// null matches any type if a value is nullable or only null in the
+	// sampled elements
+	sampled := base.optional || base.nullable || next.optional || next.nullable
+	switch {
+	case sampled && next.shape == jsonTypeNull:
+		return plainShape(base.shape), plainShape(base.shape)
+	case sampled && base.shape == jsonTypeNull:
+		return plainShape(next.shape), plainShape(next.shape)
+	}
+
+	switch baseTyped := base.shape.(type) {
+	case map[string]interface{}:
+		newTyped, ok := next.shape.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		baseAligned := make(map[string]interface{}, len(baseTyped))
+		newAligned := make(map[string]interface{}, len(newTyped))
+		for key, baseChild := range baseTyped {
+			newChild, ok := newTyped[key]
+			switch {
+			case ok:
+				baseAligned[key], newAligned[key] = alignShapes(baseChild, newChild)
+			case !asUnion(baseChild).optional:
+				baseAligned[key] = plainShape(baseChild)
+			}
+		}
+		for key, newChild := range newTyped {
+			if _, ok := baseTyped[key]; !ok && !asUnion(newChild).optional {
+				newAligned[key] = plainShape(newChild)
+			}
+		}
+
+		return baseAligned, newAligned
+	case []interface{}:
+		newTyped, ok := next.shape.([]interface{})
+		if !ok {
+			break
+		}
+
+		switch {
+		case len(baseTyped) == 0:
+			return plainShape(newTyped), plainShape(newTyped)
+		case len(newTyped) == 0:
+			return plainShape(baseTyped), plainShape(baseTyped)
+		}
+
+		baseElement, newElement := alignShapes(baseTyped[0], newTyped[0])
+
+		return []interface{}{baseElement}, []interface{}{newElement}
+	}
+
+	return plainShape(base.shape), plainShape(next.shape)
+}
+
+// plainShape removes the union flags of shape.
+func plainShape(shape interface{}) interface{} {
+	switch typed := asUnion(shape).shape.(type) {
+	case map[string]interface{}:
+		plain := make(map[string]interface{}, len(typed))
+		for key, child := range typed {
+			plain[key] = plainShape(child)
+		}
+
+		return plain
+	case []interface{}:
+		plain := make([]interface{}, 0, len(typed))
+		for _, child := range typed {
+			plain = append(plain, plainShape(child))
+		}
+
+		return plain
+	default:
+		return typed
+	}
+}
+
+// shapeDifference reports the type names of a shape instead of the type of
+// the shape itself (which is string for every scalar).
+func shapeDifference(difference Difference, element jd.DiffElement) Difference {
+	difference.OldType = shapeType(element.OldValues, difference.OldType)
+	difference.NewType = shapeType(element.NewValues, difference.NewType)
+
+	if difference.Kind == DifferenceChanged {
+		difference.Kind = DifferenceTypeChanged
+		difference.Severity = severities[DifferenceTypeChanged]
+	}
+
+	return difference
+}
+
+func shapeType(values []jd.JsonNode, valueType string) string {
+	if valueType != "string" {
+		return valueType
+	}
+
+	var shape string
+	_ = json.Unmarshal([]byte(values[0].Json()), &shape)
+
+	return shape
+}
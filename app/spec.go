@@ -0,0 +1,74 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/phux/apijc/openapi"
+)
+
+var (
+	ErrNoMatchingOperation = errors.New("no operation in the OpenAPI document matches the target")
+	ErrSpecViolation       = errors.New("response violates the OpenAPI document")
+)
+
+// ValidateAgainstOpenAPI enables validating all responses against the
+// operations of doc, in addition to comparing them.
+func (a *App) ValidateAgainstOpenAPI(doc *openapi.Document) {
+	a.spec = doc
+}
+
+// findOperation returns the operation of the OpenAPI document matching the
+// target. Targets without a matching operation are reported as warnings, an
+// undocumented endpoint is a gap in the document, not a breaking change.
+func (a *App) findOperation(target Target, relativePath string) (openapi.Operation, bool) {
+	if a.spec == nil {
+		return openapi.Operation{}, false
+	}
+
+	operation, ok := a.spec.FindOperation(target.HTTPMethod, relativePath)
+	if !ok {
+		a.addFinding(target, Finding{
+			URL:        relativePath,
+			HTTPMethod: target.HTTPMethod,
+			Severity:   SeverityWarning,
+		}, ErrNoMatchingOperation)
+	}
+
+	return operation, ok
+}
+
+// validateAgainstSpec adds a finding for each response body that does not
+// match the response of the operation for its status code.
+func (a *App) validateAgainstSpec(target Target, operation openapi.Operation, finding Finding, baseBodyJSON, newBodyJSON []byte) error {
+	baseViolations, err := a.specViolations(operation, finding.BaseStatusCode, baseBodyJSON)
+	if err != nil {
+		return err
+	}
+
+	newViolations, err := a.specViolations(operation, finding.NewStatusCode, newBodyJSON)
+	if err != nil {
+		return err
+	}
+
+	a.addViolations(target, finding, DomainBase, baseViolations, ErrSpecViolation)
+	a.addViolations(target, finding, DomainNew, newViolations, ErrSpecViolation)
+
+	return nil
+}
+
+func (a *App) specViolations(operation openapi.Operation, statusCode int, body []byte) ([]Violation, error) {
+	if !operation.ResponseStatusDocumented(statusCode) {
+		return []Violation{{
+			Pointer: "",
+			Message: fmt.Sprintf("status code %d is not documented for %s", statusCode, operation),
+		}}, nil
+	}
+
+	schema, err := a.spec.ResponseSchema(operation, statusCode)
+	if err != nil || schema == nil {
+		return nil, err
+	}
+
+	return validateResponseBody(schema, body), nil
+}
@@ -0,0 +1,155 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/openapi"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCheckTarget_WithOpenAPI(t *testing.T) {
+	doc, err := openapi.Load("../.testdata/openapi.yaml")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		target           app.Target
+		baseBody         string
+		newBody          string
+		expectedFindings []app.Finding
+	}{
+		{
+			name: "both responses match the spec",
+			target: app.Target{
+				RelativePath:       "/v1/items/1",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+			},
+			baseBody: `{"id": 1, "name": null}`,
+			newBody:  `{"id": 1, "name": null}`,
+		},
+		{
+			name: "new response violates the spec",
+			target: app.Target{
+				RelativePath:       "/v1/items/1",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+			},
+			baseBody: `{"id": 1, "name": "a"}`,
+			newBody:  `{"id": "1", "name": "a"}`,
+			expectedFindings: []app.Finding{
+				{
+					URL:    "http://localhost:5678/v1/items/1",
+					Domain: app.DomainNew,
+					Error:  app.ErrSpecViolation.Error(),
+					Violations: []app.Violation{
+						{Pointer: "/id", Message: "expected integer, but got string"},
+					},
+				},
+				{
+					URL:   "/v1/items/1",
+					Error: app.ErrJSONMismatch.Error(),
+				},
+			},
+		},
+		{
+			name: "status code is not documented",
+			target: app.Target{
+				RelativePath:       "/v1/items/1",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 500,
+			},
+			baseBody: `{}`,
+			newBody:  `{}`,
+			expectedFindings: []app.Finding{
+				{
+					URL:    "http://localhost:1234/v1/items/1",
+					Domain: app.DomainBase,
+					Error:  app.ErrSpecViolation.Error(),
+					Violations: []app.Violation{
+						{Pointer: "", Message: "status code 500 is not documented for GET /v1/items/{id}"},
+					},
+				},
+				{
+					URL:    "http://localhost:5678/v1/items/1",
+					Domain: app.DomainNew,
+					Error:  app.ErrSpecViolation.Error(),
+					Violations: []app.Violation{
+						{Pointer: "", Message: "status code 500 is not documented for GET /v1/items/{id}"},
+					},
+				},
+			},
+		},
+		{
+			name: "target without operation",
+			target: app.Target{
+				RelativePath:       "/v1/unknown",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+			},
+			baseBody: `{}`,
+			newBody:  `{}`,
+			expectedFindings: []app.Finding{
+				{
+					URL:      "/v1/unknown",
+					Error:    app.ErrNoMatchingOperation.Error(),
+					Severity: app.SeverityWarning,
+				},
+			},
+		},
+		{
+			name: "target without operation is still compared",
+			target: app.Target{
+				RelativePath:       "/v1/unknown",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+			},
+			baseBody: `{"id": 1}`,
+			newBody:  `{"id": "1"}`,
+			expectedFindings: []app.Finding{
+				{
+					URL:      "/v1/unknown",
+					Error:    app.ErrNoMatchingOperation.Error(),
+					Severity: app.SeverityWarning,
+				},
+				{
+					URL:      "/v1/unknown",
+					Error:    app.ErrJSONMismatch.Error(),
+					Severity: app.SeverityBreaking,
+				},
+			},
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			baseDomain := "http://localhost:1234"
+			newDomain := "http://localhost:5678"
+
+			defer gock.Off()
+			gock.New(baseDomain).Get(tt.target.RelativePath).Reply(tt.target.ExpectedStatusCode).BodyString(tt.baseBody)
+			gock.New(newDomain).Get(tt.target.RelativePath).Reply(tt.target.ExpectedStatusCode).BodyString(tt.newBody)
+
+			a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+			a.ValidateAgainstOpenAPI(doc)
+			_, _, err := a.CheckTarget(tt.target)
+			assert.NoError(t, err)
+
+			assert.Len(t, a.Results.Findings, len(tt.expectedFindings))
+			for i, expected := range tt.expectedFindings {
+				assert.Equal(t, expected.URL, a.Results.Findings[i].URL)
+				assert.Equal(t, expected.Domain, a.Results.Findings[i].Domain)
+				assert.Equal(t, expected.Error, a.Results.Findings[i].Error)
+				assert.Equal(t, expected.Violations, a.Results.Findings[i].Violations)
+				if expected.Severity != "" {
+					assert.Equal(t, expected.Severity, a.Results.Findings[i].Severity)
+				}
+			}
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
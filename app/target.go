@@ -1,12 +1,145 @@
 package app
 
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+)
+
 type Target struct {
 	RelativePath       string            `json:"relativePath"`
 	HTTPMethod         string            `json:"httpMethod"`
 	ExpectedStatusCode int               `json:"expectedStatusCode"`
-	RequestBody        *string           `json:"requestBody"`
-	RequestBodyFile    *string           `json:"requestBodyFile"`
-	RequestHeaders     map[string]string `json:"requestHeaders"`
+	RequestBody        *string           `json:"requestBody,omitempty"`
+	RequestBodyFile    *string           `json:"requestBodyFile,omitempty"`
+	RequestHeaders     map[string]string `json:"requestHeaders,omitempty"`
 	PatternPrefix      *string           `json:"patternPrefix,omitempty"`
 	PatternSuffix      *string           `json:"patternSuffix,omitempty"`
+	TransformBase      []Transformation  `json:"transformBase,omitempty"`
+	TransformNew       []Transformation  `json:"transformNew,omitempty"`
+	CompareMode        string            `json:"compareMode,omitempty"`
+	Equivalences       []Equivalence     `json:"equivalences,omitempty"`
+	Fields             []FieldRule       `json:"fields,omitempty"`
+	ResponseSchema     *string           `json:"responseSchema,omitempty"`
+
+	// requestBodyFileContent is the interpolated content of RequestBodyFile,
+	// if it contains variable references.
+	requestBodyFileContent *string
+	// interpolated marks the parts of the request containing resolved
+	// variable references, which may be secrets.
+	interpolated interpolatedValues
+}
+
+// interpolatedValues marks the parts of a request containing resolved
+// variable references.
+type interpolatedValues struct {
+	body bool
+	// headers by their canonical name
+	headers map[string]bool
+	// query parameters by their name
+	query map[string]bool
+	// path segments by their index counted from the end of the path
+	segments map[int]bool
+}
+
+// UnmarshalJSON allows the requestBody to be given as JSON value (e.g. an
+// object), which is sent in its compact JSON encoding, instead of a string.
+func (t *Target) UnmarshalJSON(data []byte) error {
+	type plain Target
+
+	var target struct {
+		plain
+		RequestBody json.RawMessage `json:"requestBody"`
+	}
+
+	if err := json.Unmarshal(data, &target); err != nil {
+		return err
+	}
+
+	*t = Target(target.plain)
+
+	body := bytes.TrimSpace(target.RequestBody)
+	switch {
+	case len(body) == 0 || bytes.Equal(body, []byte("null")):
+		t.RequestBody = nil
+	case body[0] == '"':
+		var str string
+		if err := json.Unmarshal(body, &str); err != nil {
+			return err
+		}
+		t.RequestBody = &str
+	default:
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, body); err != nil {
+			return err
+		}
+		str := compact.String()
+		t.RequestBody = &str
+	}
+
+	return nil
+}
+
+// readRequestBodyFile returns the interpolated content of RequestBodyFile.
+func (t Target) readRequestBodyFile() ([]byte, error) {
+	if t.requestBodyFileContent != nil {
+		return []byte(*t.requestBodyFileContent), nil
+	}
+
+	return os.ReadFile(*t.RequestBodyFile)
+}
+
+// WithDefaults returns t with all unset fields taken from defaults. The
+// requestHeaders are merged, the headers of t take precedence. The
+// relativePath is never taken from defaults, and neither is a request body if
+// t has a requestBody or requestBodyFile.
+func (t Target) WithDefaults(defaults Target) Target {
+	if t.HTTPMethod == "" {
+		t.HTTPMethod = defaults.HTTPMethod
+	}
+	if t.ExpectedStatusCode == 0 {
+		t.ExpectedStatusCode = defaults.ExpectedStatusCode
+	}
+	if t.RequestBody == nil && t.RequestBodyFile == nil {
+		t.RequestBody = defaults.RequestBody
+		t.RequestBodyFile = defaults.RequestBodyFile
+		t.requestBodyFileContent = defaults.requestBodyFileContent
+		t.interpolated.body = defaults.interpolated.body
+	}
+	if len(defaults.RequestHeaders) > 0 {
+		headers := make(map[string]string, len(defaults.RequestHeaders)+len(t.RequestHeaders))
+		for key, value := range defaults.RequestHeaders {
+			headers[key] = value
+		}
+		for key, value := range t.RequestHeaders {
+			headers[key] = value
+		}
+		t.RequestHeaders = headers
+	}
+	if t.PatternPrefix == nil {
+		t.PatternPrefix = defaults.PatternPrefix
+	}
+	if t.PatternSuffix == nil {
+		t.PatternSuffix = defaults.PatternSuffix
+	}
+	if len(t.TransformBase) == 0 {
+		t.TransformBase = defaults.TransformBase
+	}
+	if len(t.TransformNew) == 0 {
+		t.TransformNew = defaults.TransformNew
+	}
+	if t.CompareMode == "" {
+		t.CompareMode = defaults.CompareMode
+	}
+	if len(t.Equivalences) == 0 {
+		t.Equivalences = defaults.Equivalences
+	}
+	if len(t.Fields) == 0 {
+		t.Fields = defaults.Fields
+	}
+	if t.ResponseSchema == nil {
+		t.ResponseSchema = defaults.ResponseSchema
+	}
+
+	return t
 }
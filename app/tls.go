@@ -0,0 +1,73 @@
+package app
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+var (
+	ErrInvalidCACert        = errors.New("no certificates found in caCert")
+	ErrClientCertWithoutKey = errors.New("clientCert and clientKey must be given together")
+)
+
+// TLSConfig configures the TLS connections to both domains.
+type TLSConfig struct {
+	// InsecureSkipVerify disables verifying the server certificates.
+	InsecureSkipVerify bool
+	// CACert is a PEM file with CA certificates to verify the server
+	// certificates with, in addition to the system CAs.
+	CACert string
+	// ClientCert and ClientKey are PEM files of a client certificate for
+	// mutual TLS.
+	ClientCert string
+	ClientKey  string
+}
+
+// ConfigureTLS makes all requests with config instead of the default TLS
+// settings.
+func (a *App) ConfigureTLS(config TLSConfig) error {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if config.CACert != "" {
+		pem, err := os.ReadFile(config.CACert)
+		if err != nil {
+			return fmt.Errorf("cannot read caCert: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%s: %w", config.CACert, ErrInvalidCACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (config.ClientCert == "") != (config.ClientKey == "") {
+		return ErrClientCertWithoutKey
+	}
+
+	if config.ClientCert != "" {
+		certificate, err := tls.LoadX509KeyPair(config.ClientCert, config.ClientKey)
+		if err != nil {
+			return fmt.Errorf("cannot load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaultTransport.Clone()
+	}
+	transport.TLSClientConfig = tlsConfig
+	a.client = &http.Client{Transport: transport}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package app_test
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureTLS(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	})
+	baseServer := httptest.NewTLSServer(handler)
+	defer baseServer.Close()
+	newServer := httptest.NewTLSServer(handler)
+	defer newServer.Close()
+
+	caCert := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: baseServer.Certificate().Raw,
+	}), 0o644))
+
+	tests := []struct {
+		name             string
+		config           *app.TLSConfig
+		expectedErr      error
+		expectedFindings int
+	}{
+		{
+			name:             "default settings reject unknown certificates",
+			expectedFindings: 1,
+		},
+		{
+			name:   "insecureSkipVerify",
+			config: &app.TLSConfig{InsecureSkipVerify: true},
+		},
+		{
+			name:   "caCert",
+			config: &app.TLSConfig{CACert: caCert},
+		},
+		{
+			name:        "clientCert without clientKey",
+			config:      &app.TLSConfig{ClientCert: caCert},
+			expectedErr: app.ErrClientCertWithoutKey,
+		},
+		{
+			name:        "caCert without certificates",
+			config:      &app.TLSConfig{CACert: "../.testdata/request_body.json"},
+			expectedErr: app.ErrInvalidCACert,
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			a := app.NewApp(baseServer.URL, newServer.URL, app.NewURLParser(), 1000, app.Headers{})
+			if tt.config != nil {
+				err := a.ConfigureTLS(*tt.config)
+				assert.ErrorIs(t, err, tt.expectedErr)
+				if tt.expectedErr != nil {
+					return
+				}
+			}
+
+			_, _, err := a.CheckTarget(app.Target{
+				RelativePath:       "/foo",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+			})
+			assert.NoError(t, err)
+			assert.Len(t, a.Results.Findings, tt.expectedFindings)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnknownTransformationOp     = errors.New("unknown transformation op, must be one of rename|move|delete")
+	ErrTransformationMissingTo     = errors.New("transformation requires a `to` value")
+	ErrTransformationWildcardNotOK = errors.New("transformation does not support wildcards in this position")
+)
+
+const (
+	TransformationRename = "rename"
+	TransformationMove   = "move"
+	TransformationDelete = "delete"
+)
+
+// Transformation is a declarative rewrite of a parsed response body, applied
+// before comparison to express known differences between base and new.
+//
+//   - rename: renames the key at Path to To (a plain key, not a path)
+//   - move: moves the value at Path to the JSON path To
+//   - delete: removes the value at Path
+type Transformation struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	To   string `json:"to,omitempty"`
+}
+
+func applyTransformations(doc interface{}, transformations []Transformation) (interface{}, error) {
+	for _, transformation := range transformations {
+		var err error
+		doc, err = transformation.apply(doc)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", transformation.Op, transformation.Path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func (t Transformation) apply(doc interface{}) (interface{}, error) {
+	path, err := parseJSONPath(t.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.Op {
+	case TransformationDelete:
+		doc, _, err = modifyPath(doc, path, func(interface{}) (interface{}, bool, error) {
+			return nil, false, nil
+		})
+
+		return doc, err
+	case TransformationRename:
+		return t.rename(doc, path)
+	case TransformationMove:
+		return t.move(doc, path)
+	}
+
+	return nil, ErrUnknownTransformationOp
+}
+
+func (t Transformation) rename(doc interface{}, path jsonPath) (interface{}, error) {
+	if t.To == "" {
+		return nil, ErrTransformationMissingTo
+	}
+	if len(path) == 0 || path[len(path)-1] == jsonPathWildcard {
+		return nil, ErrTransformationWildcardNotOK
+	}
+
+	oldKey := path[len(path)-1]
+	doc, _, err := modifyPath(doc, path[:len(path)-1], func(parent interface{}) (interface{}, bool, error) {
+		object, ok := parent.(map[string]interface{})
+		if !ok {
+			return parent, true, nil
+		}
+
+		if value, ok := object[oldKey]; ok {
+			delete(object, oldKey)
+			object[t.To] = value
+		}
+
+		return object, true, nil
+	})
+
+	return doc, err
+}
+
+func (t Transformation) move(doc interface{}, path jsonPath) (interface{}, error) {
+	if t.To == "" {
+		return nil, ErrTransformationMissingTo
+	}
+
+	to, err := parseJSONPath(t.To)
+	if err != nil {
+		return nil, err
+	}
+	if path.hasWildcard() || to.hasWildcard() {
+		return nil, ErrTransformationWildcardNotOK
+	}
+
+	var (
+		value interface{}
+		found bool
+	)
+	doc, _, err = modifyPath(doc, path, func(v interface{}) (interface{}, bool, error) {
+		value, found = v, true
+
+		return nil, false, nil
+	})
+	if err != nil || !found {
+		return doc, err
+	}
+
+	return setPath(doc, to, value)
+}
@@ -0,0 +1,107 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCheckTarget_WithTransformations(t *testing.T) {
+	tests := []struct {
+		name          string
+		baseBody      string
+		newBody       string
+		transformBase []app.Transformation
+		transformNew  []app.Transformation
+		expectedDiff  string
+		expectedError string
+	}{
+		{
+			name:     "renamed field matches after rename",
+			baseBody: `{"id": 1, "name": "foo"}`,
+			newBody:  `{"id": 1, "title": "foo"}`,
+			transformBase: []app.Transformation{
+				{Op: app.TransformationRename, Path: "/name", To: "title"},
+			},
+		},
+		{
+			name:     "rename in every array element",
+			baseBody: `{"items": [{"name": "a"}, {"name": "b"}]}`,
+			newBody:  `{"items": [{"title": "a"}, {"title": "b"}]}`,
+			transformNew: []app.Transformation{
+				{Op: app.TransformationRename, Path: "/items/*/title", To: "name"},
+			},
+		},
+		{
+			name:     "moved field matches after move",
+			baseBody: `{"price": 5}`,
+			newBody:  `{"pricing": {"amount": 5}}`,
+			transformBase: []app.Transformation{
+				{Op: app.TransformationMove, Path: "/price", To: "/pricing/amount"},
+			},
+		},
+		{
+			name:     "deleted field is ignored",
+			baseBody: `{"id": 1, "legacy": true}`,
+			newBody:  `{"id": 1}`,
+			transformBase: []app.Transformation{
+				{Op: app.TransformationDelete, Path: "/legacy"},
+			},
+		},
+		{
+			name:     "remaining differences are still reported",
+			baseBody: `{"name": "foo"}`,
+			newBody:  `{"title": "bar"}`,
+			transformBase: []app.Transformation{
+				{Op: app.TransformationRename, Path: "/name", To: "title"},
+			},
+			expectedDiff: "@ [\"title\"]\n- \"foo\"\n+ \"bar\"\n",
+		},
+		{
+			name:     "unknown op",
+			baseBody: `{}`,
+			newBody:  `{}`,
+			transformNew: []app.Transformation{
+				{Op: "copy", Path: "/foo"},
+			},
+			expectedError: "new response: transformations: copy /foo: unknown transformation op, must be one of rename|move|delete",
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			baseDomain := "http://localhost:1234"
+			newDomain := "http://localhost:5678"
+
+			defer gock.Off()
+			gock.New(baseDomain).Get("/foo").Reply(200).BodyString(tt.baseBody)
+			gock.New(newDomain).Get("/foo").Reply(200).BodyString(tt.newBody)
+
+			a := app.NewApp(baseDomain, newDomain, app.NewURLParser(), 1000, app.Headers{})
+			_, _, err := a.CheckTarget(app.Target{
+				RelativePath:       "/foo",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+				TransformBase:      tt.transformBase,
+				TransformNew:       tt.transformNew,
+			})
+			assert.NoError(t, err)
+
+			switch {
+			case tt.expectedError != "":
+				assert.Len(t, a.Results.Findings, 1)
+				assert.Equal(t, tt.expectedError, a.Results.Findings[0].Error)
+			case tt.expectedDiff != "":
+				assert.Len(t, a.Results.Findings, 1)
+				assert.Equal(t, tt.expectedDiff, a.Results.Findings[0].Diff)
+			default:
+				assert.Empty(t, a.Results.Findings)
+			}
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
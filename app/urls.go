@@ -1,14 +1,28 @@
 package app
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+)
+
+var (
+	ErrIncludeCycle      = errors.New("include cycle")
+	ErrDuplicateSequence = errors.New("duplicate sequentialTargets name")
+	ErrUnknownField      = errors.New("unknown field")
 )
 
 type URLs struct {
+	// Defaults are applied to every target of the file that is missing a
+	// value, see Target.WithDefaults.
+	Defaults *Target `json:"defaults,omitempty"`
+	// Include lists further urlFiles whose targets are added, relative to
+	// the including file.
+	Include           []string            `json:"include,omitempty"`
 	Targets           []Target            `json:"targets"`
-	SequentialTargets map[string][]Target `json:"sequentialTargets"`
+	SequentialTargets map[string][]Target `json:"sequentialTargets,omitempty"`
 }
 
 func NewURLs(targets []Target, sequentialTargets map[string][]Target) *URLs {
@@ -18,15 +32,199 @@ func NewURLs(targets []Target, sequentialTargets map[string][]Target) *URLs {
 	}
 }
 
+// LoadURLsFromFile reads a urlFile in JSON, YAML or TOML format. The defaults
+// of each file are applied to its targets and included files are resolved,
+// so the returned URLs contain neither. Files without any targets (e.g. empty
+// files) return ErrNoTargetsDefined.
 func LoadURLsFromFile(path string) (*URLs, error) {
-	file, _ := os.ReadFile(path)
+	urls, err := loadURLsFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(urls.Targets) == 0 && len(urls.SequentialTargets) == 0 {
+		return nil, fmt.Errorf("%s: %w", path, ErrNoTargetsDefined)
+	}
+
+	return urls, nil
+}
 
+// loadURLsFromFile loads path and merges the files it includes into it.
+func loadURLsFromFile(path string) (*URLs, error) {
 	var urls *URLs
 
-	err := json.Unmarshal(file, &urls)
-	if err != nil {
-		return nil, fmt.Errorf("cannot unmarshal %s file: %w", path, err)
+	walker := urlFileWalker{
+		visit: func(_ string, file *URLs) {
+			if urls == nil {
+				urls = file
+			} else {
+				urls.add(file)
+			}
+		},
+		report: func(err *fileError) error {
+			return err
+		},
+		sequences: map[string]string{},
 	}
+	if err := walker.walk(path, "", "", nil); err != nil {
+		return nil, err
+	}
+
+	urls.Include = nil
 
 	return urls, nil
 }
+
+// fileError is an error at location of a urlFile, the location is empty for
+// errors of the whole file.
+type fileError struct {
+	file     string
+	location string
+	err      error
+}
+
+func (e *fileError) Error() string {
+	if e.location == "" {
+		return e.file + ": " + e.err.Error()
+	}
+
+	return e.file + ": " + e.location + ": " + e.err.Error()
+}
+
+func (e *fileError) Unwrap() error {
+	return e.err
+}
+
+// urlFileWalker walks a urlFile and the files it includes depth first. It is
+// shared by loading and validating, so both resolve includes the same way.
+type urlFileWalker struct {
+	// disallowUnknownFields reports every key that is no field of URLs.
+	disallowUnknownFields bool
+	// visit is called with every file after its defaults are applied and its
+	// variables are interpolated, before its includes are walked.
+	visit func(path string, urls *URLs)
+	// report is called with every error. The walk stops if it returns an
+	// error, otherwise it continues with the next file if the failed file
+	// could not be loaded.
+	report func(err *fileError) error
+	// sequences maps the names of all sequentialTargets to their file, as
+	// they must be unique across included files.
+	sequences map[string]string
+}
+
+// walk walks path, which is included at includeLocation of includedBy (both
+// empty for the first file). including are the absolute paths of all
+// including files.
+func (w *urlFileWalker) walk(path, includedBy, includeLocation string, including []string) error {
+	// a file that cannot be loaded is reported at its include
+	reportFile, reportLocation := path, ""
+	if includedBy != "" {
+		reportFile, reportLocation = includedBy, includeLocation
+	}
+
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return w.report(&fileError{file: reportFile, location: reportLocation, err: err})
+	}
+
+	for _, includingPath := range including {
+		if includingPath == absolutePath {
+			return w.report(&fileError{file: reportFile, location: reportLocation, err: fmt.Errorf("%w: %s", ErrIncludeCycle, path)})
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return w.report(&fileError{file: reportFile, location: reportLocation, err: fmt.Errorf("cannot read: %w", err)})
+	}
+
+	if w.disallowUnknownFields {
+		var doc interface{}
+		if err := decodeFile(path, content, &doc); err != nil {
+			return w.report(&fileError{file: path, err: fmt.Errorf("cannot unmarshal: %w", err)})
+		}
+
+		for _, location := range unknownFields(doc, reflect.TypeOf(URLs{}), "") {
+			if err := w.report(&fileError{file: path, location: location, err: ErrUnknownField}); err != nil {
+				return err
+			}
+		}
+	}
+
+	var urls *URLs
+	if err := decodeFile(path, content, &urls); err != nil {
+		return w.report(&fileError{file: path, err: fmt.Errorf("cannot unmarshal: %w", err)})
+	}
+
+	// empty and comment-only YAML files decode to nil
+	if urls == nil {
+		urls = &URLs{}
+	}
+
+	urls.applyDefaults()
+	if err := interpolateURLs(urls); err != nil {
+		if err := w.report(&fileError{file: path, err: err}); err != nil {
+			return err
+		}
+	}
+
+	w.visit(path, urls)
+
+	for _, name := range sortedSequenceNames(urls.SequentialTargets) {
+		first, ok := w.sequences[name]
+		if !ok {
+			w.sequences[name] = path
+
+			continue
+		}
+
+		err := fmt.Errorf("%w %q, already defined in %s", ErrDuplicateSequence, name, first)
+		if err := w.report(&fileError{file: path, location: joinLocation("sequentialTargets", name), err: err}); err != nil {
+			return err
+		}
+	}
+
+	for i, includePath := range urls.Include {
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(filepath.Dir(path), includePath)
+		}
+
+		err := w.walk(includePath, path, fmt.Sprintf("include[%d]", i), append(including, absolutePath))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyDefaults applies the defaults to all targets and removes them.
+func (u *URLs) applyDefaults() {
+	if u.Defaults == nil {
+		return
+	}
+
+	for i := range u.Targets {
+		u.Targets[i] = u.Targets[i].WithDefaults(*u.Defaults)
+	}
+	for _, targets := range u.SequentialTargets {
+		for i := range targets {
+			targets[i] = targets[i].WithDefaults(*u.Defaults)
+		}
+	}
+
+	u.Defaults = nil
+}
+
+// add appends the targets and sequential targets of other, whose names must
+// not be taken yet.
+func (u *URLs) add(other *URLs) {
+	u.Targets = append(u.Targets, other.Targets...)
+
+	if len(other.SequentialTargets) > 0 && u.SequentialTargets == nil {
+		u.SequentialTargets = map[string][]Target{}
+	}
+	for name, targets := range other.SequentialTargets {
+		u.SequentialTargets[name] = targets
+	}
+}
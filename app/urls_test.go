@@ -1,6 +1,8 @@
 package app_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/phux/apijc/app"
@@ -119,3 +121,158 @@ func TestLoadURLsFromFile(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadURLsFromFile_Formats(t *testing.T) {
+	t.Parallel()
+
+	want := &app.URLs{
+		Targets: []app.Target{
+			{
+				RelativePath:       "/v1/example",
+				HTTPMethod:         "POST",
+				ExpectedStatusCode: 201,
+				RequestHeaders:     map[string]string{"Content-Type": "application/json"},
+				RequestBody:        stringPointer(`{"a":"b","list":[1,2]}`),
+			},
+			{
+				RelativePath:       "/v1/example",
+				HTTPMethod:         "POST",
+				ExpectedStatusCode: 201,
+				RequestBody:        stringPointer(`{"a":"b"}`),
+			},
+		},
+		SequentialTargets: map[string][]app.Target{
+			"First POST, then GET": {
+				{
+					RelativePath:       "/v1/sequential_get",
+					HTTPMethod:         "GET",
+					ExpectedStatusCode: 200,
+				},
+			},
+		},
+	}
+
+	for _, path := range []string{
+		"../.testdata/urlfile_formats.json",
+		"../.testdata/urlfile_formats.yaml",
+		"../.testdata/urlfile_formats.toml",
+	} {
+		path := path
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := app.LoadURLsFromFile(path)
+
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestLoadURLsFromFile_DefaultsAndInclude(t *testing.T) {
+	t.Parallel()
+
+	got, err := app.LoadURLsFromFile("../.testdata/urlfile_include.yaml")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &app.URLs{
+		Targets: []app.Target{
+			{
+				RelativePath:       "/v1/users",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+				RequestHeaders:     map[string]string{"Accept": "application/json", "X-Team": "core"},
+			},
+			{
+				RelativePath:       "/v1/users",
+				HTTPMethod:         "POST",
+				ExpectedStatusCode: 201,
+				RequestHeaders:     map[string]string{"Accept": "application/json", "X-Team": "users"},
+			},
+			{
+				RelativePath:       "/v1/orders/<1,2>",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+				PatternPrefix:      stringPointer("<"),
+				PatternSuffix:      stringPointer(">"),
+			},
+		},
+		SequentialTargets: map[string][]app.Target{
+			"Create order": {
+				{
+					RelativePath:       "/v1/orders",
+					HTTPMethod:         "POST",
+					ExpectedStatusCode: 201,
+					PatternPrefix:      stringPointer("<"),
+					PatternSuffix:      stringPointer(">"),
+				},
+			},
+		},
+	}, got)
+}
+
+func TestLoadURLsFromFile_IncludeCycle(t *testing.T) {
+	t.Parallel()
+
+	_, err := app.LoadURLsFromFile("../.testdata/include/cycle_a.yaml")
+
+	assert.ErrorIs(t, err, app.ErrIncludeCycle)
+}
+
+func TestLoadURLsFromFile_DuplicateSequence(t *testing.T) {
+	t.Parallel()
+
+	_, err := app.LoadURLsFromFile("../.testdata/validate/urlfile.yaml")
+
+	assert.ErrorIs(t, err, app.ErrDuplicateSequence)
+	assert.EqualError(t, err, "../.testdata/validate/included.yaml: sequentialTargets.Create user: "+
+		`duplicate sequentialTargets name "Create user", already defined in ../.testdata/validate/urlfile.yaml`)
+}
+
+func TestLoadURLsFromFile_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := app.LoadURLsFromFile("../.testdata/missing.json")
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLoadURLsFromFile_EmptyFile(t *testing.T) {
+	t.Parallel()
+
+	for _, file := range []struct {
+		name    string
+		content string
+	}{
+		{name: "empty.yaml", content: ""},
+		{name: "comments.yaml", content: "# no targets yet\n"},
+		{name: "comments.toml", content: "# no targets yet\n"},
+		{name: "null.json", content: "null"},
+	} {
+		file := file
+		t.Run(file.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), file.name)
+			assert.NoError(t, os.WriteFile(path, []byte(file.content), 0o644))
+
+			urls, err := app.LoadURLsFromFile(path)
+
+			assert.ErrorIs(t, err, app.ErrNoTargetsDefined)
+			assert.Nil(t, urls)
+		})
+	}
+}
+
+func TestLoadHeadersFromFile(t *testing.T) {
+	t.Parallel()
+
+	got, err := app.LoadHeadersFromFile("../.testdata/headers.yaml")
+
+	assert.NoError(t, err)
+	assert.Equal(t, app.Headers{
+		Global:     app.HeaderKV{"Accept": "application/json"},
+		BaseDomain: app.HeaderKV{"Authorization": "Bearer base"},
+		NewDomain:  app.HeaderKV{"Authorization": "Bearer new"},
+	}, got)
+}
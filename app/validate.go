@@ -0,0 +1,381 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxExpansion is the default maximum number of paths a target may
+// expand to, see ValidateOptions.
+const DefaultMaxExpansion = 1000
+
+// Problem is an invalid value in a urlFile.
+type Problem struct {
+	File string `json:"file"`
+	// Location is the path of the value in the file, e.g.
+	// targets[0].httpMethod, empty for problems of the whole file.
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (p Problem) String() string {
+	if p.Location == "" {
+		return p.File + ": " + p.Message
+	}
+
+	return p.File + ": " + p.Location + ": " + p.Message
+}
+
+// ValidateOptions configures ValidateURLFile.
+type ValidateOptions struct {
+	// MaxExpansion is the maximum number of paths a target may expand to,
+	// DefaultMaxExpansion if 0.
+	MaxExpansion int
+}
+
+var httpMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+// ValidateURLFile checks the urlFile at path and all files it includes
+// without making requests and returns all problems found. Unlike
+// LoadURLsFromFile it rejects unknown fields.
+func ValidateURLFile(path string, opts ValidateOptions) []Problem {
+	if opts.MaxExpansion == 0 {
+		opts.MaxExpansion = DefaultMaxExpansion
+	}
+
+	v := &validator{
+		opts:      opts,
+		app:       &App{parser: NewURLParser()},
+		requested: map[string]string{},
+	}
+
+	walker := urlFileWalker{
+		disallowUnknownFields: true,
+		visit:                 v.validateURLs,
+		report: func(err *fileError) error {
+			v.problems = append(v.problems, Problem{File: err.file, Location: err.location, Message: err.err.Error()})
+
+			return nil
+		},
+		sequences: map[string]string{},
+	}
+	_ = walker.walk(path, "", "", nil)
+
+	if v.files > 0 && v.targets == 0 {
+		v.addProblem(path, "", "%s", ErrNoTargetsDefined)
+	}
+
+	return v.problems
+}
+
+type validator struct {
+	opts     ValidateOptions
+	app      *App
+	problems []Problem
+	// requested maps the requests of all targets to the location of the
+	// first target making them, to find duplicates.
+	requested map[string]string
+	// files and targets count the loaded files and their targets.
+	files   int
+	targets int
+}
+
+func (v *validator) addProblem(file, location, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{
+		File:     file,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// validateURLs validates the targets of the file at path.
+func (v *validator) validateURLs(path string, urls *URLs) {
+	v.files++
+	v.targets += len(urls.Targets) + len(urls.SequentialTargets)
+
+	for i, target := range urls.Targets {
+		location := fmt.Sprintf("targets[%d]", i)
+		relativePaths := v.validateTarget(path, location, target)
+		v.validateDuplicates(path, location, target, relativePaths)
+	}
+	for _, name := range sortedSequenceNames(urls.SequentialTargets) {
+		location := joinLocation("sequentialTargets", name)
+		for i, target := range urls.SequentialTargets[name] {
+			v.validateTarget(path, fmt.Sprintf("%s[%d]", location, i), target)
+		}
+	}
+}
+
+// unknownFields returns the locations of all keys of doc that are no fields
+// of typ.
+func unknownFields(doc interface{}, typ reflect.Type, location string) []string {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	locations := []string{}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		fields := map[string]reflect.Type{}
+		for i := 0; i < typ.NumField(); i++ {
+			name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			if name != "" && name != "-" {
+				fields[name] = typ.Field(i).Type
+			}
+		}
+
+		for _, key := range sortedKeys(object) {
+			fieldType, ok := fields[key]
+			if !ok {
+				locations = append(locations, joinLocation(location, key))
+
+				continue
+			}
+
+			// the requestBody can be any JSON value
+			if typ == reflect.TypeOf(Target{}) && key == "requestBody" {
+				continue
+			}
+
+			locations = append(locations, unknownFields(object[key], fieldType, joinLocation(location, key))...)
+		}
+	case reflect.Slice:
+		list, ok := doc.([]interface{})
+		if !ok {
+			return nil
+		}
+
+		for i, item := range list {
+			locations = append(locations, unknownFields(item, typ.Elem(), fmt.Sprintf("%s[%d]", location, i))...)
+		}
+	case reflect.Map:
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		for _, key := range sortedKeys(object) {
+			locations = append(locations, unknownFields(object[key], typ.Elem(), joinLocation(location, key))...)
+		}
+	}
+
+	return locations
+}
+
+// validateTarget reports the problems of target and returns the paths it
+// expands to.
+func (v *validator) validateTarget(file, location string, target Target) []string {
+	at := func(field string) string {
+		return joinLocation(location, field)
+	}
+
+	switch {
+	case target.HTTPMethod == "":
+		v.addProblem(file, at("httpMethod"), "missing")
+	case !containsString(httpMethods, target.HTTPMethod):
+		v.addProblem(file, at("httpMethod"), "invalid method %q, must be one of %s", target.HTTPMethod, strings.Join(httpMethods, "|"))
+	}
+
+	switch {
+	case target.ExpectedStatusCode == 0:
+		v.addProblem(file, at("expectedStatusCode"), "missing")
+	case target.ExpectedStatusCode < 100 || target.ExpectedStatusCode > 599:
+		v.addProblem(file, at("expectedStatusCode"), "invalid status code %d", target.ExpectedStatusCode)
+	}
+
+	if target.RequestBody != nil && target.RequestBodyFile != nil {
+		v.addProblem(file, location, "%s", ErrBothRequestBodyAndRequestBodyFileGiven)
+	}
+	if target.RequestBodyFile != nil {
+		if _, err := os.Stat(*target.RequestBodyFile); err != nil {
+			v.addProblem(file, at("requestBodyFile"), "%s: %s", ErrRequestBodyFileNotFound, err)
+		}
+	}
+	if target.ResponseSchema != nil {
+		if _, err := os.Stat(*target.ResponseSchema); err != nil {
+			v.addProblem(file, at("responseSchema"), "could not find responseSchema: %s", err)
+		}
+	}
+
+	switch target.CompareMode {
+	case "", CompareModeExact, CompareModeCompatible, CompareModeShape:
+	default:
+		v.addProblem(file, at("compareMode"), "%q: %s", target.CompareMode, ErrUnknownCompareMode)
+	}
+
+	if target.RelativePath == "" {
+		v.addProblem(file, at("relativePath"), "missing")
+
+		return nil
+	}
+
+	return v.validateExpansion(file, location, target)
+}
+
+// validateExpansion reports invalid pattern delimiters and path expansions of
+// target and returns the paths it expands to.
+func (v *validator) validateExpansion(file, location string, target Target) []string {
+	opts, err := v.app.buildOptsFromTarget(target)
+	if err != nil {
+		v.addProblem(file, location, "%s", err)
+
+		return nil
+	}
+
+	if opts.PatternPrefix == "" || opts.PatternSuffix == "" {
+		v.addProblem(file, location, "patternPrefix and patternSuffix cannot be empty")
+
+		return nil
+	}
+
+	for _, delimiter := range []struct{ name, value string }{
+		{"patternPrefix", opts.PatternPrefix},
+		{"patternSuffix", opts.PatternSuffix},
+	} {
+		if problem := delimiterProblem(delimiter.value); problem != "" {
+			v.addProblem(file, location, "%s %q %s", delimiter.name, delimiter.value, problem)
+
+			return nil
+		}
+	}
+
+	// the parser uses the delimiters in a regular expression unescaped, so
+	// they must match themselves
+	probe := opts.PatternPrefix + "1" + opts.PatternSuffix
+	pattern, err := regexp.Compile(opts.PatternPrefix + "([a-zA-Z0-9,-.]+)" + opts.PatternSuffix)
+	if err != nil || pattern.FindString(probe) != probe {
+		v.addProblem(
+			file, location,
+			"patternPrefix %q and patternSuffix %q cannot have a special meaning in regular expressions",
+			opts.PatternPrefix, opts.PatternSuffix,
+		)
+
+		return nil
+	}
+
+	prefixes := strings.Count(target.RelativePath, opts.PatternPrefix)
+	suffixes := strings.Count(target.RelativePath, opts.PatternSuffix)
+	if opts.PatternPrefix == opts.PatternSuffix {
+		prefixes, suffixes = (prefixes+1)/2, prefixes/2
+	}
+	if prefixes != suffixes {
+		v.addProblem(
+			file, joinLocation(location, "relativePath"),
+			"unbalanced pattern delimiters in %q: %d %q, %d %q",
+			target.RelativePath, prefixes, opts.PatternPrefix, suffixes, opts.PatternSuffix,
+		)
+
+		return nil
+	}
+
+	relativePaths, err := v.app.parser.ParsePath(target.RelativePath, opts)
+	if err != nil {
+		v.addProblem(file, joinLocation(location, "relativePath"), "%s", err)
+
+		return nil
+	}
+
+	if len(relativePaths) > v.opts.MaxExpansion {
+		v.addProblem(
+			file, joinLocation(location, "relativePath"),
+			"%q expands to %d paths, more than %d",
+			target.RelativePath, len(relativePaths), v.opts.MaxExpansion,
+		)
+	}
+
+	return relativePaths
+}
+
+// delimiterProblem returns why delimiter cannot delimit path expansions,
+// empty if it can.
+func delimiterProblem(delimiter string) string {
+	if utf8.RuneCountInString(delimiter) != 1 {
+		return "must be a single character"
+	}
+
+	// characters of expansions (see ParsePath) and of path separators
+	if strings.ContainsAny(delimiter, ",-./?&=") || unicode.IsLetter([]rune(delimiter)[0]) || unicode.IsDigit([]rune(delimiter)[0]) {
+		return "cannot be a letter, digit or one of , - . / ? & ="
+	}
+
+	return ""
+}
+
+// validateDuplicates reports if target makes a request another target
+// already makes.
+func (v *validator) validateDuplicates(file, location string, target Target, relativePaths []string) {
+	for _, relativePath := range relativePaths {
+		key := requestKey(target, relativePath)
+		if first, ok := v.requested[key]; ok {
+			v.addProblem(file, location, "duplicate request %s %s, already made by %s", target.HTTPMethod, relativePath, first)
+
+			return
+		}
+	}
+
+	for _, relativePath := range relativePaths {
+		v.requested[requestKey(target, relativePath)] = file + ": " + location
+	}
+}
+
+// requestKey identifies the request target makes to relativePath.
+func requestKey(target Target, relativePath string) string {
+	key := []string{target.HTTPMethod, relativePath}
+	if target.RequestBody != nil {
+		key = append(key, "body", *target.RequestBody)
+	}
+	if target.RequestBodyFile != nil {
+		key = append(key, "bodyFile", *target.RequestBodyFile)
+	}
+
+	headers := make([]string, 0, len(target.RequestHeaders))
+	for name, value := range target.RequestHeaders {
+		headers = append(headers, http.CanonicalHeaderKey(name)+": "+value)
+	}
+	sort.Strings(headers)
+
+	return strings.Join(append(key, headers...), "\x00")
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
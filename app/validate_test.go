@@ -0,0 +1,72 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateURLFile(t *testing.T) {
+	t.Parallel()
+
+	const (
+		urlFile  = "../.testdata/validate/urlfile.yaml"
+		included = "../.testdata/validate/included.yaml"
+	)
+
+	tests := []struct {
+		name string
+		path string
+		opts app.ValidateOptions
+		want []app.Problem
+	}{
+		{
+			name: "valid file",
+			path: "../.testdata/urlfile_include.yaml",
+			want: nil,
+		},
+		{
+			name: "missing file",
+			path: "../.testdata/validate/missing.yaml",
+			want: []app.Problem{
+				{File: "../.testdata/validate/missing.yaml", Message: "cannot read: open ../.testdata/validate/missing.yaml: no such file or directory"},
+			},
+		},
+		{
+			name: "all problems of all included files",
+			path: urlFile,
+			opts: app.ValidateOptions{MaxExpansion: 100},
+			want: []app.Problem{
+				{File: urlFile, Location: "sequentialTargets.Create user[0].transformNew[0].form", Message: "unknown field"},
+				{File: urlFile, Location: "targets[1].expectedStatusCod", Message: "unknown field"},
+				{File: urlFile, Location: "targets[1].expectedStatusCode", Message: "missing"},
+				{File: urlFile, Location: "targets[1]", Message: "duplicate request GET /v1/users/2, already made by " + urlFile + ": targets[0]"},
+				{File: urlFile, Location: "targets[2].httpMethod", Message: `invalid method "get", must be one of GET|HEAD|POST|PUT|PATCH|DELETE|CONNECT|OPTIONS|TRACE`},
+				{File: urlFile, Location: "targets[2].requestBodyFile", Message: "could not find requestBodyFile: stat does_not_exist.json: no such file or directory"},
+				{File: urlFile, Location: "targets[3].relativePath", Message: `"/v1/items/{1-5000}" expands to 5000 paths, more than 100`},
+				{File: urlFile, Location: "targets[4]", Message: `patternPrefix "(" and patternSuffix ")" cannot have a special meaning in regular expressions`},
+				{File: urlFile, Location: "targets[5].relativePath", Message: `unbalanced pattern delimiters in "/v1/items/{1,2": 1 "{", 0 "}"`},
+				{File: included, Location: "targets[0]", Message: "duplicate request GET /v1/users/3, already made by " + urlFile + ": targets[0]"},
+				{File: included, Location: "targets[1].expectedStatusCode", Message: "invalid status code 600"},
+				{File: included, Location: "targets[1].compareMode", Message: `"strict": unknown compareMode, must be one of exact|compatible|shape`},
+				{File: included, Location: "targets[2]", Message: `patternPrefix "<<" must be a single character`},
+				{File: included, Location: "targets[3]", Message: `patternPrefix "x" cannot be a letter, digit or one of , - . / ? & =`},
+				{File: included, Location: "sequentialTargets.Create user", Message: `duplicate sequentialTargets name "Create user", already defined in ` + urlFile},
+				{File: urlFile, Location: "include[1]", Message: "cannot read: open ../.testdata/validate/missing.yaml: no such file or directory"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := app.ValidateURLFile(tt.path, tt.opts)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,445 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultConfigFile is read from the working directory if --config is
+	// not given.
+	defaultConfigFile = "apijc.yaml"
+	// envPrefix is the prefix of the environment variables of the flags,
+	// e.g. APIJC_BASE_DOMAIN for --baseDomain.
+	envPrefix = "APIJC_"
+)
+
+// sources of flag values, from highest to lowest precedence
+const (
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceProfile = "profile"
+	sourceConfig  = "config"
+	sourceDefault = "default"
+)
+
+// keys of the config file that are not flags
+const (
+	configKeyProfiles = "profiles"
+	configKeyHeaders  = "headers"
+)
+
+var (
+	ErrNoConfigFile   = errors.New("no config file found")
+	ErrUnknownProfile = errors.New("unknown profile")
+)
+
+var (
+	configFile string
+	profile    string
+	// flagSources are the sources of the values of all flags applied so far.
+	flagSources = map[string]string{}
+	// configHeaders are the headers of the config file and the selected
+	// profile.
+	configHeaders app.Headers
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "inspect the configuration",
+	Long: `inspect the configuration.
+
+Every flag can be set via an APIJC_* environment variable (e.g. APIJC_BASE_DOMAIN
+for --baseDomain) or in the config file (apijc.yaml in the working directory or
+--config). Precedence: flag > environment variable > config file > default.`,
+}
+
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "print the effective configuration of a comparison run",
+	Long: `print the effective configuration of a comparison run as YAML, which can be used as
+config file. The source of each value is added as comment. Accepts the flags of a
+comparison run, which take precedence like in a run. The headers of the config file,
+the profile and --headerFile are printed merged, with secrets redacted unless
+--showSecrets is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		headers, err := loadHeaders()
+		if err != nil {
+			return err
+		}
+
+		if !showSecrets {
+			headers = headers.Redacted()
+		}
+
+		content, err := printConfig(cmd.Flags(), headers)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(content)
+
+		return nil
+	},
+}
+
+func init() {
+	// set here, as applyConfig refers to rootCmd
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return applyConfig(cmd.Flags())
+	}
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configPrintCmd)
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "[optional] profile: name of the profile in the config file to apply")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "[optional] config: YAML file with flag values (default: \""+defaultConfigFile+"\" in the working directory, if it exists)")
+}
+
+// applyConfig sets all flags not given on the command line from their
+// environment variable, the selected profile or the config file.
+func applyConfig(flags *pflag.FlagSet) error {
+	path := configFile
+	if value, ok := os.LookupEnv(envName("config")); ok && path == "" {
+		path = value
+	}
+
+	profileName := profile
+	if value, ok := os.LookupEnv(envName("profile")); ok && profileName == "" {
+		profileName = value
+	}
+
+	config, err := loadConfig(path, profileName)
+	if err != nil {
+		return err
+	}
+	configHeaders = config.headers
+
+	var errs []error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if _, ok := flagSources[flag.Name]; ok || isConfigFlag(flag.Name) {
+			return
+		}
+
+		if flag.Changed {
+			flagSources[flag.Name] = sourceFlag
+
+			return
+		}
+
+		if value, ok := os.LookupEnv(envName(flag.Name)); ok {
+			if err := flags.Set(flag.Name, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", envName(flag.Name), err))
+			}
+			flagSources[flag.Name] = sourceEnv
+
+			return
+		}
+
+		if value, ok := config.values[flag.Name]; ok {
+			if err := setConfigValue(flags, flag, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", config.path, flag.Name, err))
+			}
+			flagSources[flag.Name] = config.sources[flag.Name]
+
+			return
+		}
+
+		flagSources[flag.Name] = sourceDefault
+	})
+
+	return errors.Join(errs...)
+}
+
+// config is the content of the config file, with the values of the selected
+// profile applied.
+type config struct {
+	path string
+	// values are the flag values by flag name
+	values map[string]interface{}
+	// sources are the sources of values, the config file or the profile
+	sources map[string]string
+	headers app.Headers
+}
+
+// loadConfig reads the config file at path, or the default config file if
+// path is empty and it exists, and applies the profile with profileName.
+// Keys must be flag names, headers or profiles.
+func loadConfig(path, profileName string) (*config, error) {
+	loaded := &config{
+		path:    path,
+		values:  map[string]interface{}{},
+		sources: map[string]string{},
+	}
+
+	if path == "" {
+		if _, err := os.Stat(defaultConfigFile); err != nil {
+			if profileName != "" {
+				return nil, fmt.Errorf("profile %q: %w", profileName, ErrNoConfigFile)
+			}
+
+			return loaded, nil
+		}
+		loaded.path = defaultConfigFile
+	}
+
+	content, err := os.ReadFile(loaded.path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal %s file: %w", loaded.path, err)
+	}
+
+	profiles, _ := values[configKeyProfiles].(map[string]interface{})
+	delete(values, configKeyProfiles)
+
+	if err := loaded.apply(values, sourceConfig); err != nil {
+		return nil, err
+	}
+
+	if profileName == "" {
+		return loaded, nil
+	}
+
+	profileValues, ok := profiles[profileName].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf(
+			"%s: %q: %w, available: %s",
+			loaded.path, profileName, ErrUnknownProfile, strings.Join(sortedKeys(profiles), ", "),
+		)
+	}
+
+	if err := loaded.apply(profileValues, sourceProfile+" "+profileName); err != nil {
+		return nil, err
+	}
+
+	return loaded, nil
+}
+
+// apply adds the flag values and headers of values, overriding the values
+// applied before.
+func (c *config) apply(values map[string]interface{}, source string) error {
+	known := flagNames(rootCmd)
+	for key, value := range values {
+		if key == configKeyHeaders {
+			headers, err := decodeHeaders(value)
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", c.path, source, err)
+			}
+			c.headers = c.headers.Merge(headers)
+
+			continue
+		}
+
+		if !known[key] || isConfigFlag(key) {
+			return fmt.Errorf("%s: %s: unknown key %q, must be a flag name like baseDomain, headers or profiles", c.path, source, key)
+		}
+
+		c.values[key] = value
+		c.sources[key] = source
+	}
+
+	return nil
+}
+
+// decodeHeaders converts the headers object of the config file, which has
+// the structure of a headerFile.
+func decodeHeaders(value interface{}) (app.Headers, error) {
+	var headers app.Headers
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return headers, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&headers); err != nil {
+		return headers, fmt.Errorf("invalid headers: %w", err)
+	}
+
+	return headers, headers.Interpolate()
+}
+
+func setConfigValue(flags *pflag.FlagSet, flag *pflag.Flag, value interface{}) error {
+	list, isList := value.([]interface{})
+	sliceValue, isSlice := flag.Value.(pflag.SliceValue)
+	if !isList || !isSlice {
+		return flags.Set(flag.Name, fmt.Sprint(value))
+	}
+
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		values = append(values, fmt.Sprint(item))
+	}
+	flag.Changed = true
+
+	return sliceValue.Replace(values)
+}
+
+// addRunFlags adds the flags of a comparison run to cmd, so it uses the same
+// values as a run. The flags share their values with the flags of rootCmd,
+// but are not required.
+func addRunFlags(cmd *cobra.Command) {
+	rootCmd.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		copied := *flag
+		copied.Annotations = nil
+		cmd.Flags().AddFlag(&copied)
+	})
+}
+
+// printConfig renders the values of flags and headers as YAML with their
+// sources as comments.
+func printConfig(flags *pflag.FlagSet, headers app.Headers) (string, error) {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if isConfigFlag(flag.Name) {
+			return
+		}
+
+		value := &yaml.Node{Kind: yaml.ScalarNode, Value: flag.Value.String()}
+		switch flag.Value.Type() {
+		case "bool", "int", "float64":
+		case "stringSlice":
+			value = &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+			for _, item := range flag.Value.(pflag.SliceValue).GetSlice() {
+				value.Content = append(value.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+			}
+		default:
+			value.Tag = "!!str"
+		}
+
+		value.LineComment = flagSources[flag.Name]
+		if value.LineComment == sourceEnv {
+			value.LineComment += " " + envName(flag.Name)
+		}
+
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: flag.Name}, value)
+	})
+
+	if headersNode := headersNode(headers); headersNode != nil {
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: configKeyHeaders}, headersNode)
+	}
+
+	if profileName := profile; profileName != "" || os.Getenv(envName("profile")) != "" {
+		if profileName == "" {
+			profileName = os.Getenv(envName("profile"))
+		}
+		doc.HeadComment = "profile: " + profileName
+	}
+
+	var content strings.Builder
+	encoder := yaml.NewEncoder(&content)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return "", err
+	}
+
+	return content.String(), nil
+}
+
+// headersNode renders the non-empty parts of headers with sorted names, nil
+// if there are no headers.
+func headersNode(headers app.Headers) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, part := range []struct {
+		name    string
+		headers app.HeaderKV
+	}{
+		{"global", headers.Global},
+		{"baseDomain", headers.BaseDomain},
+		{"newDomain", headers.NewDomain},
+	} {
+		if len(part.headers) == 0 {
+			continue
+		}
+
+		names := make([]string, 0, len(part.headers))
+		for name := range part.headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		values := &yaml.Node{Kind: yaml.MappingNode}
+		for _, name := range names {
+			values.Content = append(
+				values.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: name},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part.headers[name]},
+			)
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: part.name}, values)
+	}
+
+	if len(node.Content) == 0 {
+		return nil
+	}
+
+	return node
+}
+
+// flagNames returns the names of the flags of cmd and all its subcommands.
+func flagNames(cmd *cobra.Command) map[string]bool {
+	names := map[string]bool{}
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		names[flag.Name] = true
+	})
+
+	for _, subcommand := range cmd.Commands() {
+		for name := range flagNames(subcommand) {
+			names[name] = true
+		}
+	}
+
+	return names
+}
+
+// isConfigFlag reports whether name is a flag selecting the configuration,
+// which cannot be set in the config file itself.
+func isConfigFlag(name string) bool {
+	return name == "config" || name == "profile" || name == "help"
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// envName returns the environment variable of a flag, e.g. APIJC_BASE_DOMAIN
+// for baseDomain or APIJC_OPEN_API_FILE for openAPIFile.
+func envName(flag string) string {
+	var name strings.Builder
+	runes := []rune(flag)
+	for i, char := range runes {
+		// a word starts at an upper case letter after a lower case one, or at
+		// the last upper case letter of an acronym, e.g. openAPIFile
+		startsWord := i > 0 && unicode.IsUpper(char) &&
+			(!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1]))
+		if startsWord {
+			name.WriteRune('_')
+		}
+		name.WriteRune(unicode.ToUpper(char))
+	}
+
+	return envPrefix + name.String()
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestApplyConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "apijc.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+baseDomain: http://config
+newDomain: http://config
+outputFile: config.json
+harOut: config.har
+profiles:
+  staging:
+    baseDomain: http://profile
+    newDomain: http://profile
+    outputFile: profile.json
+`), 0o644))
+
+	defer func(path, name string, sources map[string]string) {
+		configFile, profile, flagSources = path, name, sources
+	}(configFile, profile, flagSources)
+	configFile, profile, flagSources = path, "staging", map[string]string{}
+
+	t.Setenv(envName("baseDomain"), "http://env")
+	t.Setenv(envName("newDomain"), "http://env")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	for _, name := range []string{"baseDomain", "newDomain", "outputFile", "harOut", "artifactsDir"} {
+		flags.String(name, "default", "")
+	}
+	assert.NoError(t, flags.Parse([]string{"--baseDomain", "http://flag"}))
+
+	assert.NoError(t, applyConfig(flags))
+
+	tests := []struct {
+		flag       string
+		wantValue  string
+		wantSource string
+	}{
+		{flag: "baseDomain", wantValue: "http://flag", wantSource: sourceFlag},
+		{flag: "newDomain", wantValue: "http://env", wantSource: sourceEnv},
+		{flag: "outputFile", wantValue: "profile.json", wantSource: "profile staging"},
+		{flag: "harOut", wantValue: "config.har", wantSource: sourceConfig},
+		{flag: "artifactsDir", wantValue: "default", wantSource: sourceDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			value, err := flags.GetString(tt.flag)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantValue, value)
+			assert.Equal(t, tt.wantSource, flagSources[tt.flag])
+		})
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		flag string
+		want string
+	}{
+		{flag: "config", want: "APIJC_CONFIG"},
+		{flag: "baseDomain", want: "APIJC_BASE_DOMAIN"},
+		{flag: "insecureSkipVerify", want: "APIJC_INSECURE_SKIP_VERIFY"},
+		{flag: "openAPIFile", want: "APIJC_OPEN_API_FILE"},
+		{flag: "useHTTP2", want: "APIJC_USE_HTTP2"},
+		{flag: "caURL", want: "APIJC_CA_URL"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.flag, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, envName(tt.flag))
+		})
+	}
+}
+
+func TestSetConfigValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "list", content: "domainAlias: [a.example.com, b.example.com]", want: []string{"a.example.com", "b.example.com"}},
+		{name: "list with numbers", content: "domainAlias: [1, two]", want: []string{"1", "two"}},
+		{name: "comma separated string", content: "domainAlias: a.example.com,b.example.com", want: []string{"a.example.com", "b.example.com"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.StringSlice("domainAlias", []string{"default.example.com"}, "")
+
+			values := map[string]interface{}{}
+			assert.NoError(t, yaml.Unmarshal([]byte(tt.content), &values))
+
+			err := setConfigValue(flags, flags.Lookup("domainAlias"), values["domainAlias"])
+
+			assert.NoError(t, err)
+			got, err := flags.GetStringSlice("domainAlias")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/openapi"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	coverageOpenAPIFile string
+	coverageOutputFile  string
+)
+
+var coverageCmd = &cobra.Command{
+	Use:   "coverage",
+	Short: "report which OpenAPI operations the urlFile covers",
+	Long: `report which OpenAPI operations the targets of the urlFile (after path expansion) cover.
+
+Lists operations never exercised, operations only exercised with 2xx status codes
+and targets that match no operation. No requests are made.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if urlFile == "" {
+			log.Fatalln("Error: --urlFile is required")
+		}
+
+		urls, err := app.LoadURLsFromFile(urlFile)
+		if err != nil {
+			log.Fatalf("Error: %s\n", err)
+		}
+
+		doc, err := openapi.Load(coverageOpenAPIFile)
+		if err != nil {
+			log.Fatalf("Error: %s\n", err)
+		}
+
+		a := app.NewApp("", "", app.NewURLParser(), rateLimit, app.Headers{})
+		a.AddURLs(*urls)
+
+		report, err := a.Coverage(doc)
+		if err != nil {
+			log.Fatalf("Error: %s\n", err)
+		}
+
+		if coverageOutputFile != "" {
+			content, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			err = os.WriteFile(coverageOutputFile, content, 0o644)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			log.Printf("Written coverage report to %s", coverageOutputFile)
+
+			return
+		}
+
+		printCoverageReport(report, len(doc.Operations))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(coverageCmd)
+	coverageCmd.Flags().StringVar(&coverageOpenAPIFile, "openapi", "", "[required] openapi: OpenAPI 3 document (YAML or JSON) to map the targets to")
+	coverageCmd.MarkFlagRequired("openapi")
+	coverageCmd.Flags().StringVar(&coverageOutputFile, "outputFile", "", "[optional] outputFile: path to write the report to as JSON (default: \"\" -> writing to stdout)")
+}
+
+func printCoverageReport(report *app.CoverageReport, countOperations int) {
+	fmt.Printf(
+		"Covered %d of %d operations\n",
+		countOperations-len(report.UncoveredOperations),
+		countOperations,
+	)
+
+	fmt.Printf("\nOperations never exercised (%d):\n", len(report.UncoveredOperations))
+	for _, operation := range report.UncoveredOperations {
+		fmt.Printf("  %s\n", operation)
+	}
+
+	fmt.Printf("\nOperations only exercised with 2xx status codes (%d):\n", len(report.HappyPathOnlyOperations))
+	for _, operation := range report.HappyPathOnlyOperations {
+		uncovered := ""
+		if len(operation.UncoveredStatusCodes) > 0 {
+			uncovered = " (not exercised: " + strings.Join(operation.UncoveredStatusCodes, ", ") + ")"
+		}
+		fmt.Printf("  %s%s\n", operation.Operation, uncovered)
+	}
+
+	fmt.Printf("\nTargets matching no operation (%d):\n", len(report.UnmatchedTargets))
+	for _, target := range report.UnmatchedTargets {
+		fmt.Printf("  %s %s\n", target.HTTPMethod, target.RelativePath)
+	}
+}
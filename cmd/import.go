@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/importer"
+
+	"github.com/spf13/cobra"
+)
+
+var importOutputFile string
+
+// importCmd groups the subcommands creating a urlFile from other formats
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "create a urlFile from other formats",
+	Long:  `create a urlFile from other formats. The urlFile is written to stdout or --out.`,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.PersistentFlags().StringVar(&importOutputFile, "out", "", "[optional] out: path to write the urlFile to (default: \"\" -> writing to stdout)")
+}
+
+// writeURLs writes urls as urlFile to --out or stdout and logs the warnings
+// of the import.
+func writeURLs(urls *app.URLs, warnings []string) {
+	for _, warning := range warnings {
+		log.Printf("Warning: %s\n", warning)
+	}
+
+	content, err := encodeURLs(urls)
+	if err != nil {
+		log.Fatalf("Error: %s\n", err)
+	}
+
+	if importOutputFile == "" {
+		fmt.Print(string(content))
+
+		return
+	}
+
+	err = os.WriteFile(importOutputFile, content, 0o644)
+	if err != nil {
+		log.Fatalf("Error: %s\n", err)
+	}
+
+	log.Printf("Written %d targets to %s\n", len(urls.Targets), importOutputFile)
+}
+
+// encodeURLs encodes urls as indented JSON followed by a new line. Characters
+// like & are not escaped, so imported queries stay readable.
+func encodeURLs(urls *app.URLs) ([]byte, error) {
+	var content bytes.Buffer
+
+	encoder := json.NewEncoder(&content)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(urls); err != nil {
+		return nil, err
+	}
+
+	return content.Bytes(), nil
+}
+
+// stripHeaders returns the request headers to remove from imported requests:
+// importer.DefaultStripHeaders unless keepDefaults is set, and extra.
+func stripHeaders(extra []string, keepDefaults bool) []string {
+	headers := []string{}
+	if !keepDefaults {
+		headers = append(headers, importer.DefaultStripHeaders...)
+	}
+
+	return append(headers, extra...)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/phux/apijc/importer"
+
+	"github.com/spf13/cobra"
+)
+
+var accessLogOpts importer.AccessLogOptions
+
+var importAccessLogCmd = &cobra.Command{
+	Use:   "accesslog <path/to/access.log>",
+	Short: "create a urlFile from a web server access log",
+	Long: `create a urlFile from a web server access log in the nginx/Apache combined log format
+or with one JSON object per line.
+
+Requests are deduplicated, requests only differing in numeric path segments are
+collapsed into a path expansion of the most requested values. The targets are
+ordered by the number of requests, the most frequent status code becomes the
+expected status code. Only GET and HEAD requests are imported by default, as
+request bodies are not logged.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		requests, warnings, err := importer.LoadAccessLog(args[0], accessLogOpts)
+		if err != nil {
+			log.Fatalf("Error: %s\n", err)
+		}
+
+		urls, importWarnings := importer.FromAccessLog(requests, accessLogOpts)
+		writeURLs(urls, append(warnings, importWarnings...))
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importAccessLogCmd)
+	importAccessLogCmd.Flags().StringVar(&accessLogOpts.Format, "format", importer.AccessLogFormatCombined, "[optional] format: format of the access log (combined|json)")
+	importAccessLogCmd.Flags().StringVar(&accessLogOpts.MethodField, "methodField", "request_method", "[optional] methodField: field of the HTTP method in JSON logs, nested fields separated by dots")
+	importAccessLogCmd.Flags().StringVar(&accessLogOpts.PathField, "pathField", "request_uri", "[optional] pathField: field of the path (with query) in JSON logs, nested fields separated by dots")
+	importAccessLogCmd.Flags().StringVar(&accessLogOpts.StatusField, "statusField", "status", "[optional] statusField: field of the status code in JSON logs, nested fields separated by dots")
+	importAccessLogCmd.Flags().StringSliceVar(&accessLogOpts.Methods, "method", []string{"GET", "HEAD"}, "[optional] method: only import requests with these HTTP methods, can be repeated")
+	importAccessLogCmd.Flags().IntVar(&accessLogOpts.Samples, "samples", 10, "[optional] samples: maximum number of values per collapsed numeric path segment (0 -> all)")
+	importAccessLogCmd.Flags().IntVar(&accessLogOpts.Top, "top", 0, "[optional] top: only import the most requested targets (default: 0 -> all)")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/phux/apijc/importer"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	curlStripHeaders       []string
+	curlKeepDefaultHeaders bool
+)
+
+var importCurlCmd = &cobra.Command{
+	Use:   "curl [path/to/commands.sh ...]",
+	Short: "create a urlFile from curl commands",
+	Long: `create a urlFile from curl commands with one target per command, read from the given
+files or stdin (no file or -).
+
+Commands are separated by new lines and can be continued with a trailing
+backslash. The method, headers, data and URL of each command are converted, the
+host is stripped from the URL. Credentials and headers set by the HTTP client
+are stripped like in import har.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			args = []string{"-"}
+		}
+
+		inputs := make([]string, 0, len(args))
+		for _, path := range args {
+			var (
+				content []byte
+				err     error
+			)
+			if path == "-" {
+				content, err = io.ReadAll(os.Stdin)
+			} else {
+				content, err = os.ReadFile(path)
+			}
+			if err != nil {
+				log.Fatalf("Error: %s\n", err)
+			}
+
+			inputs = append(inputs, string(content))
+		}
+
+		writeURLs(importer.FromCurl(strings.Join(inputs, "\n"), importer.CurlOptions{
+			StripHeaders: stripHeaders(curlStripHeaders, curlKeepDefaultHeaders),
+		}))
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importCurlCmd)
+	importCurlCmd.Flags().StringSliceVar(&curlStripHeaders, "stripHeader", []string{}, "[optional] stripHeader: request headers to remove in addition to the default ones, can be repeated")
+	importCurlCmd.Flags().BoolVar(&curlKeepDefaultHeaders, "keepDefaultHeaders", false, "[optional] keepDefaultHeaders: keep the credentials and client headers removed by default ("+strings.Join(importer.DefaultStripHeaders, ", ")+")")
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"log"
+	"strings"
+
+	"github.com/phux/apijc/importer"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	harHosts              []string
+	harContentTypes       []string
+	harStripHeaders       []string
+	harKeepDefaultHeaders bool
+)
+
+var importHARCmd = &cobra.Command{
+	Use:   "har <path/to/recording.har>",
+	Short: "create a urlFile from a HAR file",
+	Long: `create a urlFile from a HAR file (e.g. exported from the browser dev tools) with one
+target per recorded request.
+
+The status code of the recorded response becomes the expected status code.
+Credentials (cookies, authorization) and headers set by the HTTP client are
+stripped from the request headers by default, --stripHeader removes further
+headers and --keepDefaultHeaders keeps the default ones. Entries without a
+recorded response (status 0) are skipped.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		archive, err := importer.LoadHAR(args[0])
+		if err != nil {
+			log.Fatalf("Error: %s\n", err)
+		}
+
+		writeURLs(importer.FromHAR(archive, importer.HAROptions{
+			Hosts:        harHosts,
+			ContentTypes: harContentTypes,
+			StripHeaders: stripHeaders(harStripHeaders, harKeepDefaultHeaders),
+		}))
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importHARCmd)
+	importHARCmd.Flags().StringSliceVar(&harHosts, "host", []string{}, "[optional] host: only import requests to these hosts, can be repeated (default: all hosts)")
+	importHARCmd.Flags().StringSliceVar(&harContentTypes, "contentType", []string{}, "[optional] contentType: only import requests whose response content type contains one of these values, e.g. json (default: all content types)")
+	importHARCmd.Flags().StringSliceVar(&harStripHeaders, "stripHeader", []string{}, "[optional] stripHeader: request headers to remove in addition to the default ones, can be repeated")
+	importHARCmd.Flags().BoolVar(&harKeepDefaultHeaders, "keepDefaultHeaders", false, "[optional] keepDefaultHeaders: keep the credentials and client headers removed by default ("+strings.Join(importer.DefaultStripHeaders, ", ")+")")
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/phux/apijc/importer"
+	"github.com/phux/apijc/openapi"
+
+	"github.com/spf13/cobra"
+)
+
+var importOpenAPICmd = &cobra.Command{
+	Use:   "openapi <path/to/openapi.yaml>",
+	Short: "create a urlFile from an OpenAPI 3 document",
+	Long: `create a urlFile from an OpenAPI 3 document (YAML or JSON) with one target per operation.
+
+Path parameters are filled with their example values, multiple examples become a
+path expansion. The expected status code is the first documented 2xx response.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		doc, err := openapi.Load(args[0])
+		if err != nil {
+			log.Fatalf("Error: %s\n", err)
+		}
+
+		writeURLs(importer.FromOpenAPI(doc))
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importOpenAPICmd)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/phux/apijc/importer"
+
+	"github.com/spf13/cobra"
+)
+
+var importPostmanCmd = &cobra.Command{
+	Use:   "postman <path/to/collection.json>",
+	Short: "create a urlFile from a Postman v2.1 collection",
+	Long: `create a urlFile from a Postman v2.1 collection with one target per request.
+
+Requests at the top level become targets, the requests of each folder become a
+sequentialTargets group. Collection variables are resolved, raw and urlencoded
+bodies are translated.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		collection, err := importer.LoadPostman(args[0])
+		if err != nil {
+			log.Fatalf("Error: %s\n", err)
+		}
+
+		writeURLs(importer.FromPostman(collection))
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importPostmanCmd)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/importer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripHeaders(t *testing.T) {
+	assert.Equal(t, importer.DefaultStripHeaders, stripHeaders([]string{}, false))
+	assert.Equal(t, append(append([]string{}, importer.DefaultStripHeaders...), "X-Trace"), stripHeaders([]string{"X-Trace"}, false))
+	assert.Equal(t, []string{"X-Trace"}, stripHeaders([]string{"X-Trace"}, true))
+	assert.Empty(t, stripHeaders([]string{}, true))
+}
+
+func TestEncodeURLs(t *testing.T) {
+	content, err := encodeURLs(app.NewURLs([]app.Target{
+		{RelativePath: "/search?q=a&page=<1>", HTTPMethod: "GET", ExpectedStatusCode: 200},
+	}, nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+    "targets": [
+        {
+            "relativePath": "/search?q=a&page=<1>",
+            "httpMethod": "GET",
+            "expectedStatusCode": 200
+        }
+    ]
+}
+`, string(content))
+}
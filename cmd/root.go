@@ -10,6 +10,7 @@ import (
 	"os"
 
 	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/openapi"
 
 	"github.com/spf13/cobra"
 )
@@ -21,6 +22,26 @@ var (
 	rateLimit  float64
 	outputFile string
 	headerFile string
+	failOn     string
+
+	nullEqualsMissing bool
+	emptyEqualsNull   bool
+	normalizeURLs     bool
+	domainAliases     []string
+	openAPIFile       string
+	showSecrets       bool
+	artifactsDir      string
+	harOut            string
+
+	insecureSkipVerify bool
+	caCert             string
+	clientCert         string
+	clientKey          string
+)
+
+const (
+	failOnAny      = "any"
+	failOnBreaking = "breaking"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -29,13 +50,17 @@ var rootCmd = &cobra.Command{
 	Short: "compare json responses across two domains",
 	Long:  `compare json responses across two domains.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if failOn != failOnAny && failOn != failOnBreaking {
+			log.Fatalf("Error: invalid --failOn %q, must be one of %s|%s\n", failOn, failOnAny, failOnBreaking)
+		}
+
 		fmt.Printf("\nStarting with rate limit: %f/second\n\n", rateLimit)
 		urls, err := app.LoadURLsFromFile(urlFile)
 		if err != nil {
 			log.Fatalf("Error: %s\n", err)
 		}
 
-		headers, err := loadHeadersFromFile()
+		headers, err := loadHeaders()
 		if err != nil {
 			log.Fatalln(err)
 		}
@@ -49,8 +74,47 @@ var rootCmd = &cobra.Command{
 			headers,
 		)
 		a.AddURLs(*urls)
+		if insecureSkipVerify || caCert != "" || clientCert != "" || clientKey != "" {
+			err := a.ConfigureTLS(app.TLSConfig{
+				InsecureSkipVerify: insecureSkipVerify,
+				CACert:             caCert,
+				ClientCert:         clientCert,
+				ClientKey:          clientKey,
+			})
+			if err != nil {
+				log.Fatalf("Error: %s\n", err)
+			}
+		}
+		if openAPIFile != "" {
+			doc, err := openapi.Load(openAPIFile)
+			if err != nil {
+				log.Fatalf("Error: %s\n", err)
+			}
+			a.ValidateAgainstOpenAPI(doc)
+		}
+		if showSecrets {
+			a.ShowSecrets()
+		}
+		if artifactsDir != "" {
+			a.WriteArtifacts(artifactsDir)
+		}
+		if harOut != "" {
+			a.RecordHAR()
+		}
+		if normalizeURLs {
+			a.NormalizeURLs(domainAliases...)
+		}
+		if nullEqualsMissing || emptyEqualsNull {
+			a.AddEquivalences(app.Equivalence{
+				NullEqualsMissing: nullEqualsMissing,
+				EmptyEqualsNull:   emptyEqualsNull,
+			})
+		}
 
 		err = a.Run()
+		if harOut != "" {
+			writeHAR(a)
+		}
 		if err != nil {
 			log.Fatalf("Error: %s\n", err)
 		}
@@ -69,7 +133,10 @@ var rootCmd = &cobra.Command{
 		if outputFile == "" {
 			log.Println("Findings:")
 			for _, finding := range a.Results.Findings {
-				log.Printf("%s\nError: %s\nDiff: "+finding.Diff, finding.URL, finding.Error)
+				log.Printf("%s\nError: %s\nSeverity: %s\nDiff: "+finding.Diff, finding.URL, finding.Error, finding.Severity)
+				if finding.BaseCurl != "" {
+					log.Printf("Base request: %s\nNew request: %s\n", finding.BaseCurl, finding.NewCurl)
+				}
 			}
 		} else {
 			err = os.WriteFile(outputFile, findings, 0o644)
@@ -77,7 +144,13 @@ var rootCmd = &cobra.Command{
 				log.Fatalln(err)
 			}
 
-			log.Fatalf("Written findings to %s", outputFile)
+			log.Printf("Written findings to %s", outputFile)
+		}
+
+		if failOn == failOnBreaking && len(a.Results.Breaking()) == 0 {
+			log.Printf("Finished - %d findings, none breaking", len(a.Results.Findings))
+
+			return
 		}
 
 		log.Fatalf("Finished - %d findings", len(a.Results.Findings))
@@ -91,7 +164,7 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVar(&urlFile, "urlFile", "", "[required] JSON file with relative paths, HTTP method, ...")
+	rootCmd.PersistentFlags().StringVar(&urlFile, "urlFile", "", "[required] JSON, YAML or TOML file with relative paths, HTTP method, ...")
 	rootCmd.MarkFlagRequired("urlFile")
 	rootCmd.Flags().StringVar(&baseDomain, "baseDomain", "", "[required] baseDomain: domain for the left side of the comparison")
 	rootCmd.MarkFlagRequired("baseDomain")
@@ -99,24 +172,55 @@ func init() {
 	rootCmd.MarkFlagRequired("newDomain")
 	rootCmd.Flags().Float64Var(&rateLimit, "rateLimit", 1, "[optional] rate limit of requests / second")
 	rootCmd.Flags().StringVar(&outputFile, "outputFile", "", "[optional] outputFile: path to write the findings to if > 0 findings (default: \"\" -> writing to stdout)")
-	rootCmd.Flags().StringVar(&headerFile, "headerFile", "", "[optional] headerFile: provide (additional) header key-value pairs via a JSON object (string: string). Applied to every request")
+	rootCmd.Flags().StringVar(&failOn, "failOn", failOnAny, "[optional] failOn: exit with code 1 on \"any\" finding or only on \"breaking\" findings")
+	rootCmd.Flags().BoolVar(&nullEqualsMissing, "nullEqualsMissing", false, "[optional] nullEqualsMissing: treat null and missing fields as equal in all responses")
+	rootCmd.Flags().BoolVar(&emptyEqualsNull, "emptyEqualsNull", false, "[optional] emptyEqualsNull: treat empty arrays/objects and null as equal in all responses")
+	rootCmd.Flags().BoolVar(&normalizeURLs, "normalizeURLs", false, "[optional] normalizeURLs: replace baseDomain, newDomain and domainAliases in response string values with a placeholder and ignore query parameter order of such URLs")
+	rootCmd.Flags().StringSliceVar(&domainAliases, "domainAlias", []string{}, "[optional] domainAlias: additional domain replaced if --normalizeURLs is set, e.g. https://public.example.com (repeatable)")
+	rootCmd.Flags().StringVar(&openAPIFile, "openapi", "", "[optional] openapi: OpenAPI 3 document (YAML or JSON) to validate all responses against")
+	rootCmd.Flags().BoolVar(&showSecrets, "showSecrets", false, "[optional] showSecrets: do not redact credentials in the curl commands of findings")
+	rootCmd.Flags().StringVar(&artifactsDir, "artifactsDir", "", "[optional] artifactsDir: directory to write the full requests and responses of each finding to")
+	rootCmd.Flags().StringVar(&harOut, "harOut", "", "[optional] harOut: path to write all requests and responses to as HAR 1.2 file")
+	rootCmd.Flags().BoolVar(&insecureSkipVerify, "insecureSkipVerify", false, "[optional] insecureSkipVerify: do not verify the TLS certificates of both domains")
+	rootCmd.Flags().StringVar(&caCert, "caCert", "", "[optional] caCert: PEM file with additional CA certificates to trust")
+	rootCmd.Flags().StringVar(&clientCert, "clientCert", "", "[optional] clientCert: PEM file with the client certificate for mutual TLS, requires --clientKey")
+	rootCmd.Flags().StringVar(&clientKey, "clientKey", "", "[optional] clientKey: PEM file with the private key of --clientCert")
+	rootCmd.Flags().StringVar(&headerFile, "headerFile", "", "[optional] headerFile: provide (additional) header key-value pairs via a JSON, YAML or TOML object (string: string). Applied to every request")
+
+	// set here, as the flags of config.go are registered first
+	addRunFlags(configPrintCmd)
+}
+
+// loadHeaders returns the headers of the config file and profile, overridden
+// by the headers of --headerFile.
+func loadHeaders() (app.Headers, error) {
+	headers, err := loadHeadersFromFile()
+	if err != nil {
+		return headers, err
+	}
+
+	return configHeaders.Merge(headers), nil
 }
 
 func loadHeadersFromFile() (app.Headers, error) {
-	var headers app.Headers
 	if headerFile == "" {
-		return headers, nil
+		return app.Headers{}, nil
 	}
 
-	content, err := os.ReadFile(headerFile)
+	return app.LoadHeadersFromFile(headerFile)
+}
+
+// writeHAR writes the requests and responses recorded by a to --harOut.
+func writeHAR(a *app.App) {
+	content, err := json.MarshalIndent(a.HAR(), "", "  ")
 	if err != nil {
-		return headers, err
+		log.Fatalf("Error: %s\n", err)
 	}
 
-	err = json.Unmarshal(content, &headers)
+	err = os.WriteFile(harOut, content, 0o644)
 	if err != nil {
-		return headers, err
+		log.Fatalf("Error: %s\n", err)
 	}
 
-	return headers, nil
+	log.Printf("Written %d requests to %s\n", len(a.HAR().Log.Entries), harOut)
 }
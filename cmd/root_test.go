@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadHeaders(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "headers.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte(`
+global:
+  Authorization: Bearer from-header-file
+newDomain:
+  X-Env: from-header-file
+`), 0o644))
+
+	defer func(headers app.Headers, path string) {
+		configHeaders, headerFile = headers, path
+	}(configHeaders, headerFile)
+
+	configHeaders = app.Headers{
+		Global:    app.HeaderKV{"Authorization": "Bearer from-profile", "X-Team": "core"},
+		NewDomain: app.HeaderKV{"X-Env": "from-profile"},
+	}
+	headerFile = file
+
+	headers, err := loadHeaders()
+
+	assert.NoError(t, err)
+	assert.Equal(t, app.Headers{
+		Global:    app.HeaderKV{"Authorization": "Bearer from-header-file", "X-Team": "core"},
+		NewDomain: app.HeaderKV{"X-Env": "from-header-file"},
+	}, headers)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/phux/apijc/app"
+
+	"github.com/spf13/cobra"
+)
+
+var maxExpansion int
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "check the urlFile for problems without making requests",
+	Long: `check the urlFile and all files it includes for problems without making requests.
+
+Rejects unknown fields and checks HTTP methods, status codes, pattern delimiters,
+the existence of requestBodyFiles and responseSchemas, the number of paths each
+target expands to and duplicate targets. All problems are printed at once.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if urlFile == "" {
+			log.Fatalln("Error: --urlFile is required")
+		}
+
+		problems := app.ValidateURLFile(urlFile, app.ValidateOptions{MaxExpansion: maxExpansion})
+		if len(problems) == 0 {
+			log.Printf("%s is valid", urlFile)
+
+			return
+		}
+
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+
+		log.Fatalf("Found %d problems", len(problems))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().IntVar(&maxExpansion, "maxExpansion", app.DefaultMaxExpansion, "[optional] maxExpansion: maximum number of paths a target may expand to")
+}
@@ -0,0 +1,416 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/phux/apijc/app"
+)
+
+var ErrUnknownAccessLogFormat = errors.New("unknown access log format, must be one of combined|json")
+
+const (
+	// AccessLogFormatCombined is the nginx/Apache combined (or common) log
+	// format.
+	AccessLogFormatCombined = "combined"
+	// AccessLogFormatJSON is one JSON object per line.
+	AccessLogFormatJSON = "json"
+)
+
+// combinedLogLine matches the request and the status of a line in the
+// combined or common log format, e.g.
+// 127.0.0.1 - - [10/Oct/2023:13:55:36 +0000] "GET /users/1 HTTP/1.1" 200 2326 "-" "curl/8.0".
+var combinedLogLine = regexp.MustCompile(`^\S+ \S+ \S+ \[[^\]]*\] "(\S+) (\S+)[^"]*" (\d{3}) `)
+
+// numericSegment matches path segments that are collapsed into expansions.
+// Values with leading zeros are kept as they are.
+var numericSegment = regexp.MustCompile(`^(0|[1-9][0-9]*)$`)
+
+// numericPlaceholder replaces the numeric segments in the key of a group.
+const numericPlaceholder = "\x00"
+
+type AccessLogOptions struct {
+	// Format is one of combined|json.
+	Format string
+	// MethodField, PathField and StatusField are the field names of JSON
+	// logs, nested fields are separated by dots, e.g. request.method.
+	MethodField string
+	PathField   string
+	StatusField string
+	// Methods limits the import to requests with these methods.
+	Methods []string
+	// Samples is the maximum number of values kept per collapsed path.
+	Samples int
+	// Top limits the import to the most requested targets, all if 0.
+	Top int
+}
+
+// AccessLogRequest is a single request of an access log.
+type AccessLogRequest struct {
+	Method string
+	Path   string
+	Status int
+}
+
+// LoadAccessLog reads the requests of an access log. Lines that cannot be
+// parsed are skipped and reported as warning.
+func LoadAccessLog(path string, opts AccessLogOptions) ([]AccessLogRequest, []string, error) {
+	if opts.Format != AccessLogFormatCombined && opts.Format != AccessLogFormatJSON {
+		return nil, nil, fmt.Errorf("%q: %w", opts.Format, ErrUnknownAccessLogFormat)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	defer file.Close()
+
+	requests := []AccessLogRequest{}
+	skipped := []int{}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var (
+			request AccessLogRequest
+			ok      bool
+		)
+		if opts.Format == AccessLogFormatJSON {
+			request, ok = parseJSONLogLine(line, opts)
+		} else {
+			request, ok = parseCombinedLogLine(line)
+		}
+
+		if !ok {
+			skipped = append(skipped, lineNumber)
+
+			continue
+		}
+
+		requests = append(requests, request)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	warnings := []string{}
+	if len(skipped) > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s: skipped %d lines that could not be parsed, first in line %d",
+			path, len(skipped), skipped[0],
+		))
+	}
+
+	return requests, warnings, nil
+}
+
+func parseCombinedLogLine(line string) (AccessLogRequest, bool) {
+	match := combinedLogLine.FindStringSubmatch(line)
+	if match == nil {
+		return AccessLogRequest{}, false
+	}
+
+	status, _ := strconv.Atoi(match[3])
+
+	return AccessLogRequest{Method: match[1], Path: match[2], Status: status}, true
+}
+
+func parseJSONLogLine(line string, opts AccessLogOptions) (AccessLogRequest, bool) {
+	var entry map[string]interface{}
+	if json.Unmarshal([]byte(line), &entry) != nil {
+		return AccessLogRequest{}, false
+	}
+
+	method, _ := lookupField(entry, opts.MethodField).(string)
+	path, _ := lookupField(entry, opts.PathField).(string)
+	if method == "" || path == "" {
+		return AccessLogRequest{}, false
+	}
+
+	var status int
+	switch typed := lookupField(entry, opts.StatusField).(type) {
+	case float64:
+		status = int(typed)
+	case string:
+		status, _ = strconv.Atoi(typed)
+	}
+
+	return AccessLogRequest{Method: method, Path: path, Status: status}, true
+}
+
+// lookupField returns the value of the dot separated field in entry.
+func lookupField(entry map[string]interface{}, field string) interface{} {
+	var value interface{} = entry
+	for _, key := range strings.Split(field, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+
+	return value
+}
+
+// accessLogGroup are the requests of the same method and path, with numeric
+// path segments replaced by placeholders.
+type accessLogGroup struct {
+	method string
+	// segments of the path, numeric segments are numericPlaceholder
+	segments []string
+	query    string
+	count    int
+	statuses map[int]int
+	// values counts the requests per value of the numeric segments
+	values map[string]int
+}
+
+// FromAccessLog creates targets from the requests of an access log. Requests
+// that only differ in numeric path segments are grouped, the segment values
+// become a path expansion of at most opts.Samples (most requested) values.
+// The targets are ordered by the number of requests, the most frequent status
+// code of a group becomes the expected status code.
+func FromAccessLog(requests []AccessLogRequest, opts AccessLogOptions) (*app.URLs, []string) {
+	warnings := []string{}
+	groups := map[string]*accessLogGroup{}
+
+	for _, request := range requests {
+		method := strings.ToUpper(request.Method)
+		if !containsMethod(opts.Methods, method) {
+			continue
+		}
+
+		path, query := splitAccessLogPath(request.Path)
+		if strings.ContainsAny(path, "{}") {
+			warnings = append(warnings, fmt.Sprintf("%s %s: skipped, braces conflict with path expansions", request.Method, request.Path))
+
+			continue
+		}
+
+		segments := strings.Split(path, "/")
+		values := []string{}
+		for i, segment := range segments {
+			if isNumericSegment(segment) {
+				values = append(values, segment)
+				segments[i] = numericPlaceholder
+			}
+		}
+
+		key := method + " " + strings.Join(segments, "/") + "?" + query
+
+		group, ok := groups[key]
+		if !ok {
+			group = &accessLogGroup{
+				method:   method,
+				segments: segments,
+				query:    query,
+				statuses: map[int]int{},
+				values:   map[string]int{},
+			}
+			groups[key] = group
+		}
+
+		group.count++
+		group.statuses[request.Status]++
+		group.values[strings.Join(values, "/")]++
+	}
+
+	sorted := make([]*accessLogGroup, 0, len(groups))
+	for _, group := range groups {
+		sorted = append(sorted, group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+
+		return sorted[i].key() < sorted[j].key()
+	})
+
+	urls := app.NewURLs([]app.Target{}, nil)
+	for _, group := range sorted {
+		for _, relativePath := range group.relativePaths(opts.Samples) {
+			urls.Targets = append(urls.Targets, app.Target{
+				RelativePath:       relativePath,
+				HTTPMethod:         group.method,
+				ExpectedStatusCode: group.status(),
+			})
+		}
+
+		if opts.Top > 0 && len(urls.Targets) >= opts.Top {
+			urls.Targets = urls.Targets[:opts.Top]
+
+			break
+		}
+	}
+
+	return urls, warnings
+}
+
+func (g *accessLogGroup) key() string {
+	return g.method + " " + strings.Join(g.segments, "/") + "?" + g.query
+}
+
+// status returns the most frequent status code, the lowest one on a tie.
+func (g *accessLogGroup) status() int {
+	status, count := 0, 0
+	for candidate, candidateCount := range g.statuses {
+		if candidateCount > count || candidateCount == count && candidate < status {
+			status, count = candidate, candidateCount
+		}
+	}
+
+	return status
+}
+
+// relativePaths returns a single path with a path expansion of the sampled
+// values if the path has one numeric segment, otherwise one path per sampled
+// combination of values.
+func (g *accessLogGroup) relativePaths(samples int) []string {
+	values := sampleValues(g.values, samples)
+
+	switch strings.Count(strings.Join(g.segments, "/"), numericPlaceholder) {
+	case 0:
+		return []string{g.path(nil)}
+	case 1:
+		if len(values) == 1 {
+			return []string{g.path(values)}
+		}
+
+		return []string{g.path([]string{"{" + compactNumbers(values) + "}"})}
+	}
+
+	paths := make([]string, 0, len(values))
+	for _, value := range values {
+		paths = append(paths, g.path(strings.Split(value, "/")))
+	}
+
+	return paths
+}
+
+// path fills the numeric segments with values.
+func (g *accessLogGroup) path(values []string) string {
+	segments := make([]string, len(g.segments))
+	copy(segments, g.segments)
+
+	for i, segment := range segments {
+		if segment == numericPlaceholder {
+			segments[i], values = values[0], values[1:]
+		}
+	}
+
+	path := strings.Join(segments, "/")
+	if path == "" {
+		path = "/"
+	}
+
+	if g.query != "" {
+		path += "?" + g.query
+	}
+
+	return path
+}
+
+// sampleValues returns the most requested values, at most samples (all if 0).
+func sampleValues(counts map[string]int, samples int) []string {
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+
+		return values[i] < values[j]
+	})
+
+	if samples > 0 && len(values) > samples {
+		values = values[:samples]
+	}
+
+	return values
+}
+
+// isNumericSegment reports whether segment is collapsed into an expansion.
+// Numbers beyond int64 are kept as they are, e.g. long numeric tokens.
+func isNumericSegment(segment string) bool {
+	if !numericSegment.MatchString(segment) {
+		return false
+	}
+
+	_, err := strconv.ParseInt(segment, 10, 64)
+
+	return err == nil
+}
+
+// compactNumbers sorts the numeric values and joins them as path expansion,
+// e.g. 1,2,3,5 becomes 1-3,5. The values are numeric segments, see
+// isNumericSegment.
+func compactNumbers(values []string) string {
+	numbers := make([]int64, 0, len(values))
+	for _, value := range values {
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	parts := []string{}
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] == numbers[j]+1 {
+			j++
+		}
+
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", numbers[i], numbers[j]))
+		} else {
+			parts = append(parts, strconv.FormatInt(numbers[i], 10))
+		}
+		i = j + 1
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, allowed := range methods {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitAccessLogPath returns the path and the query of a logged request
+// target, which can also be an absolute URL. Braces in the query are escaped
+// like in relativePath.
+func splitAccessLogPath(target string) (string, string) {
+	if strings.Contains(target, "://") {
+		if parsed, err := url.Parse(target); err == nil {
+			target = relativePath(parsed)
+		}
+	}
+
+	path, query, _ := strings.Cut(target, "?")
+
+	return path, queryBraces.Replace(query)
+}
@@ -0,0 +1,99 @@
+package importer_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/importer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromAccessLog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		path         string
+		opts         importer.AccessLogOptions
+		wantTargets  []app.Target
+		wantWarnings []string
+	}{
+		{
+			name: "combined log format",
+			path: "../.testdata/access.log",
+			opts: importer.AccessLogOptions{
+				Format:  importer.AccessLogFormatCombined,
+				Methods: []string{"GET", "HEAD"},
+			},
+			wantTargets: []app.Target{
+				{RelativePath: "/users/{1-3,7}", HTTPMethod: "GET", ExpectedStatusCode: 200},
+				{RelativePath: "/search?q=foo", HTTPMethod: "GET", ExpectedStatusCode: 200},
+				{RelativePath: "/tags?filter=%7Ba%7D", HTTPMethod: "GET", ExpectedStatusCode: 200},
+				{RelativePath: "/users/1/orders/5", HTTPMethod: "GET", ExpectedStatusCode: 200},
+				{RelativePath: "/users/123456789012345678901234", HTTPMethod: "GET", ExpectedStatusCode: 200},
+				{RelativePath: "/users/123456789012345678901235", HTTPMethod: "GET", ExpectedStatusCode: 200},
+				{RelativePath: "/health", HTTPMethod: "HEAD", ExpectedStatusCode: 200},
+			},
+			wantWarnings: []string{
+				"../.testdata/access.log: skipped 1 lines that could not be parsed, first in line 10",
+			},
+		},
+		{
+			name: "samples and top",
+			path: "../.testdata/access.log",
+			opts: importer.AccessLogOptions{
+				Format:  importer.AccessLogFormatCombined,
+				Methods: []string{"GET"},
+				Samples: 2,
+				Top:     2,
+			},
+			wantTargets: []app.Target{
+				{RelativePath: "/users/{1,3}", HTTPMethod: "GET", ExpectedStatusCode: 200},
+				{RelativePath: "/search?q=foo", HTTPMethod: "GET", ExpectedStatusCode: 200},
+			},
+			wantWarnings: []string{
+				"../.testdata/access.log: skipped 1 lines that could not be parsed, first in line 10",
+			},
+		},
+		{
+			name: "JSON log format with nested fields",
+			path: "../.testdata/access.json.log",
+			opts: importer.AccessLogOptions{
+				Format:      importer.AccessLogFormatJSON,
+				MethodField: "request.method",
+				PathField:   "request.uri",
+				StatusField: "status",
+				Methods:     []string{"GET"},
+			},
+			wantTargets: []app.Target{
+				{RelativePath: "/users/{1-2}", HTTPMethod: "GET", ExpectedStatusCode: 200},
+				{RelativePath: "/users/01", HTTPMethod: "GET", ExpectedStatusCode: 404},
+			},
+			wantWarnings: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			requests, warnings, err := importer.LoadAccessLog(tt.path, tt.opts)
+			assert.NoError(t, err)
+
+			urls, importWarnings := importer.FromAccessLog(requests, tt.opts)
+
+			assert.Equal(t, tt.wantTargets, urls.Targets)
+			assert.Equal(t, tt.wantWarnings, append(warnings, importWarnings...))
+		})
+	}
+}
+
+func TestLoadAccessLogUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := importer.LoadAccessLog("../.testdata/access.log", importer.AccessLogOptions{Format: "xml"})
+
+	assert.ErrorIs(t, err, importer.ErrUnknownAccessLogFormat)
+}
@@ -0,0 +1,489 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/phux/apijc/app"
+)
+
+var ErrUnterminatedQuote = errors.New("unterminated quote")
+
+// curlValueOptions are the curl options taking a value that do not influence
+// the request sent by apijc.
+var curlValueOptions = map[string]bool{
+	"-o": true, "--output": true, "-m": true, "--max-time": true, "--connect-timeout": true,
+	"-w": true, "--write-out": true, "-x": true, "--proxy": true, "--retry": true,
+	"--cacert": true, "--cert": true, "--key": true, "-E": true, "-K": true, "--config": true,
+	"--resolve": true, "--connect-to": true, "-c": true, "--cookie-jar": true,
+	"-D": true, "--dump-header": true, "-U": true, "--proxy-user": true, "-Y": true,
+	"--speed-limit": true, "-y": true, "--speed-time": true, "--retry-delay": true,
+	"--retry-max-time": true, "--max-redirs": true, "--limit-rate": true, "--max-filesize": true,
+	"--trace": true, "--trace-ascii": true, "--stderr": true, "--output-dir": true,
+	"--interface": true, "--local-port": true, "--dns-servers": true, "--noproxy": true,
+	"--capath": true, "--cert-type": true, "--key-type": true, "--ciphers": true,
+	"--pinnedpubkey": true, "--unix-socket": true, "--abstract-unix-socket": true,
+	"--netrc-file": true, "--keepalive-time": true, "--expect100-timeout": true,
+}
+
+// curlUnsupportedValueOptions are the curl options taking a value that
+// influence the request, but are not supported.
+var curlUnsupportedValueOptions = map[string]bool{
+	"-F": true, "--form": true, "--form-string": true, "-T": true, "--upload-file": true,
+	"-r": true, "--range": true, "-z": true, "--time-cond": true, "-C": true,
+	"--continue-at": true, "-Q": true, "--quote": true, "-t": true, "--telnet-option": true,
+	"--oauth2-bearer": true, "--aws-sigv4": true, "--request-target": true,
+	"--variable": true, "--url-query": true, "--proxy-header": true,
+}
+
+// curlShortValueOptions are the single letter options taking a value, which
+// can be attached to the option, e.g. -XPOST.
+const curlShortValueOptions = "XHdbuAeomwxEKcDUYyFTrzCQt"
+
+type CurlOptions struct {
+	// StripHeaders are removed from the requests (case-insensitive).
+	StripHeaders []string
+}
+
+// curlRequest collects the parts of a request while parsing a curl command.
+type curlRequest struct {
+	method      string
+	rawURL      string
+	headers     map[string]string
+	data        []string
+	dataFile    string
+	contentType string
+	get         bool
+	// impliedMethod is the method of unsupported options, e.g. POST for
+	// --form, used if no method is given.
+	impliedMethod string
+}
+
+// FromCurl creates one target per curl command in input. Commands are
+// separated by new lines, lines can be continued with a trailing backslash.
+// The host of the URL is stripped, options that do not influence the request
+// are ignored.
+func FromCurl(input string, opts CurlOptions) (*app.URLs, []string) {
+	urls := app.NewURLs([]app.Target{}, nil)
+	warnings := []string{}
+
+	commands, err := splitShellCommands(input)
+	if err != nil {
+		return urls, append(warnings, err.Error())
+	}
+
+	for i, args := range commands {
+		if len(args) > 0 && args[0] == "$" {
+			args = args[1:]
+		}
+		if len(args) == 0 || strings.HasPrefix(args[0], "#") {
+			continue
+		}
+
+		if args[0] != "curl" {
+			warnings = append(warnings, fmt.Sprintf("command %d: not a curl command, skipping it", i+1))
+
+			continue
+		}
+
+		target, commandWarnings, err := curlTarget(args[1:], opts)
+		for _, warning := range commandWarnings {
+			warnings = append(warnings, fmt.Sprintf("command %d: %s", i+1, warning))
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("command %d: %s, skipping it", i+1, err))
+
+			continue
+		}
+
+		urls.Targets = append(urls.Targets, target)
+	}
+
+	return urls, warnings
+}
+
+func curlTarget(args []string, opts CurlOptions) (app.Target, []string, error) {
+	request := curlRequest{headers: map[string]string{}}
+	warnings := []string{}
+
+	for i := 0; i < len(args); i++ {
+		options := splitCurlOptions(args[i])
+		if len(options) == 0 {
+			if request.rawURL != "" {
+				warnings = append(warnings, fmt.Sprintf("ignoring additional URL %q", args[i]))
+
+				continue
+			}
+			request.rawURL = args[i]
+
+			continue
+		}
+
+		for _, option := range options {
+			if !option.hasValue && takesValue(option.name) {
+				if i+1 >= len(args) {
+					return app.Target{}, warnings, fmt.Errorf("option %s: missing value", option.name)
+				}
+				i++
+				option.value = args[i]
+			}
+
+			warning, err := request.apply(option.name, option.value)
+			if err != nil {
+				return app.Target{}, warnings, err
+			}
+			if warning != "" {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+
+	return request.target(opts, warnings)
+}
+
+// curlOption is an option of a curl command and its attached value.
+type curlOption struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+// splitCurlOptions returns the options of arg and their attached values, e.g.
+// -XPOST, --request=POST or the flags -sSL. It returns nil if arg is not an
+// option.
+func splitCurlOptions(arg string) []curlOption {
+	switch {
+	case strings.HasPrefix(arg, "--"):
+		if name, value, ok := strings.Cut(arg, "="); ok {
+			return []curlOption{{name: name, value: value, hasValue: true}}
+		}
+
+		return []curlOption{{name: arg}}
+	case strings.HasPrefix(arg, "-") && len(arg) > 1:
+		options := []curlOption{}
+		for i := 1; i < len(arg); i++ {
+			name := "-" + arg[i:i+1]
+			if strings.Contains(curlShortValueOptions, arg[i:i+1]) && i+1 < len(arg) {
+				return append(options, curlOption{name: name, value: arg[i+1:], hasValue: true})
+			}
+			options = append(options, curlOption{name: name})
+		}
+
+		return options
+	}
+
+	return nil
+}
+
+func takesValue(option string) bool {
+	if curlValueOptions[option] || curlUnsupportedValueOptions[option] {
+		return true
+	}
+
+	switch option {
+	case "-X", "--request", "-H", "--header", "-d", "--data", "--data-raw", "--data-ascii",
+		"--data-binary", "--data-urlencode", "--json", "--url", "-u", "--user", "-b", "--cookie",
+		"-A", "--user-agent", "-e", "--referer":
+		return true
+	}
+
+	return false
+}
+
+// apply applies a single option to the request and returns a warning for
+// options that are ignored.
+func (r *curlRequest) apply(option, value string) (string, error) {
+	switch option {
+	case "-X", "--request":
+		r.method = strings.ToUpper(value)
+	case "-H", "--header":
+		name, headerValue, ok := strings.Cut(value, ":")
+		if !ok {
+			return fmt.Sprintf("ignoring invalid header %q", value), nil
+		}
+		r.headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+	case "-A", "--user-agent":
+		r.headers["User-Agent"] = value
+	case "-e", "--referer":
+		r.headers["Referer"] = value
+	case "-b", "--cookie":
+		r.headers["Cookie"] = value
+	case "-d", "--data", "--data-ascii", "--data-binary":
+		if strings.HasPrefix(value, "@") {
+			r.dataFile = value[1:]
+		} else {
+			r.data = append(r.data, value)
+		}
+		r.contentType = "application/x-www-form-urlencoded"
+	case "--data-raw":
+		r.data = append(r.data, value)
+		r.contentType = "application/x-www-form-urlencoded"
+	case "--data-urlencode":
+		name, content, ok := strings.Cut(value, "=")
+		if ok {
+			r.data = append(r.data, name+"="+url.QueryEscape(content))
+		} else {
+			r.data = append(r.data, url.QueryEscape(value))
+		}
+		r.contentType = "application/x-www-form-urlencoded"
+	case "--json":
+		if strings.HasPrefix(value, "@") {
+			r.dataFile = value[1:]
+		} else {
+			r.data = append(r.data, value)
+		}
+		r.contentType = "application/json"
+		if _, ok := r.headers["Accept"]; !ok {
+			r.headers["Accept"] = "application/json"
+		}
+	case "--url":
+		r.rawURL = value
+	case "-G", "--get":
+		r.get = true
+	case "-I", "--head":
+		r.method = "HEAD"
+	case "-u", "--user":
+		return "ignoring credentials of " + option + ", use a headerFile instead", nil
+	case "-F", "--form", "--form-string":
+		r.impliedMethod = "POST"
+
+		return fmt.Sprintf("ignoring unsupported option %s, the request body is not imported", option), nil
+	case "-T", "--upload-file":
+		r.impliedMethod = "PUT"
+
+		return fmt.Sprintf("ignoring unsupported option %s, the request body is not imported", option), nil
+	default:
+		if curlValueOptions[option] || isIgnoredCurlFlag(option) {
+			return "", nil
+		}
+
+		return fmt.Sprintf("ignoring unsupported option %s", option), nil
+	}
+
+	return "", nil
+}
+
+// isIgnoredCurlFlag reports whether option is a flag without value that does
+// not influence the request.
+func isIgnoredCurlFlag(option string) bool {
+	switch option {
+	case "--compressed", "--silent", "--show-error", "--insecure", "--location", "--verbose",
+		"--include", "--fail", "--fail-with-body", "--globoff", "--http1.1", "--http2",
+		"--no-buffer", "--no-progress-meter", "--progress-bar", "--location-trusted",
+		"--create-dirs", "--remote-name", "--remote-header-name", "--ipv4", "--ipv6",
+		"--tcp-nodelay", "--disable", "--no-keepalive", "--no-sessionid",
+		"-s", "-S", "-k", "-L", "-v", "-i", "-f", "-g", "-N", "-#", "-O", "-J", "-4", "-6", "-q":
+		return true
+	}
+
+	return false
+}
+
+func (r *curlRequest) target(opts CurlOptions, warnings []string) (app.Target, []string, error) {
+	if r.rawURL == "" {
+		return app.Target{}, warnings, errors.New("no URL")
+	}
+
+	rawURL := r.rawURL
+	if !strings.Contains(rawURL, "://") && !strings.HasPrefix(rawURL, "/") {
+		rawURL = "http://" + rawURL
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return app.Target{}, warnings, fmt.Errorf("invalid URL %q: %w", r.rawURL, err)
+	}
+
+	target := app.Target{
+		RelativePath:       relativePath(parsed),
+		HTTPMethod:         r.method,
+		ExpectedStatusCode: 200,
+	}
+
+	hasBody := len(r.data) > 0 || r.dataFile != ""
+	if r.get && hasBody {
+		if r.dataFile != "" {
+			warnings = append(warnings, "ignoring @"+r.dataFile+", -G only supports inline data")
+		}
+		separator := "?"
+		if strings.Contains(target.RelativePath, "?") {
+			separator = "&"
+		}
+		target.RelativePath += separator + queryBraces.Replace(strings.Join(r.data, "&"))
+		hasBody = false
+	}
+
+	if target.HTTPMethod == "" {
+		target.HTTPMethod = "GET"
+		if r.impliedMethod != "" {
+			target.HTTPMethod = r.impliedMethod
+		}
+		if hasBody {
+			target.HTTPMethod = "POST"
+		}
+	}
+
+	if hasBody {
+		if r.dataFile != "" {
+			dataFile := r.dataFile
+			target.RequestBodyFile = &dataFile
+		} else {
+			body := strings.Join(r.data, "&")
+			target.RequestBody = &body
+		}
+
+		if !hasHeader(r.headers, "Content-Type") {
+			r.headers["Content-Type"] = r.contentType
+		}
+	}
+
+	for name, value := range r.headers {
+		if containsHeader(opts.StripHeaders, name) {
+			continue
+		}
+
+		if target.RequestHeaders == nil {
+			target.RequestHeaders = map[string]string{}
+		}
+		target.RequestHeaders[name] = value
+	}
+
+	return target, warnings, nil
+}
+
+// splitShellCommands splits input into commands and their arguments, following
+// the quoting rules of POSIX shells: 'single quotes', "double quotes",
+// $'ANSI-C quotes' and backslash escapes. Unquoted new lines (but not escaped
+// ones) and ; separate commands.
+func splitShellCommands(input string) ([][]string, error) {
+	commands := [][]string{}
+	args := []string{}
+	var arg strings.Builder
+	inArg := false
+
+	endArg := func() {
+		if inArg {
+			args = append(args, arg.String())
+			arg.Reset()
+			inArg = false
+		}
+	}
+	endCommand := func() {
+		endArg()
+		if len(args) > 0 {
+			commands = append(commands, args)
+		}
+		args = []string{}
+	}
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); i++ {
+		char := runes[i]
+
+		switch {
+		case char == '\\':
+			if i+1 < len(runes) {
+				i++
+				if runes[i] == '\r' && i+1 < len(runes) && runes[i+1] == '\n' {
+					i++
+				}
+				if runes[i] != '\n' {
+					arg.WriteRune(runes[i])
+					inArg = true
+				}
+			}
+		case char == '\'':
+			end := indexRune(runes, '\'', i+1)
+			if end < 0 {
+				return nil, ErrUnterminatedQuote
+			}
+			arg.WriteString(string(runes[i+1 : end]))
+			inArg = true
+			i = end
+		case char == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			quoted, end, err := ansiCQuoted(runes, i+2)
+			if err != nil {
+				return nil, err
+			}
+			arg.WriteString(quoted)
+			inArg = true
+			i = end
+		case char == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						continue
+					}
+				}
+				arg.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, ErrUnterminatedQuote
+			}
+			inArg = true
+		case char == '\n' || char == ';':
+			endCommand()
+		case char == ' ' || char == '\t' || char == '\r':
+			endArg()
+		default:
+			arg.WriteRune(char)
+			inArg = true
+		}
+	}
+	endCommand()
+
+	return commands, nil
+}
+
+// ansiCQuoted returns the content of a $'...' string starting at start and
+// the index of the closing quote.
+func ansiCQuoted(runes []rune, start int) (string, int, error) {
+	var quoted strings.Builder
+	escapes := map[rune]string{'n': "\n", 't': "\t", 'r': "\r", '\\': "\\", '\'': "'", '"': "\"", '0': "\x00"}
+
+	for i := start; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\'':
+			return quoted.String(), i, nil
+		case runes[i] == '\\' && i+1 < len(runes):
+			i++
+			if escaped, ok := escapes[runes[i]]; ok {
+				quoted.WriteString(escaped)
+
+				continue
+			}
+
+			digits := map[rune]int{'x': 2, 'u': 4, 'U': 8}[runes[i]]
+			if digits > 0 && i+digits < len(runes) {
+				if code, err := strconv.ParseUint(string(runes[i+1:i+1+digits]), 16, 32); err == nil {
+					quoted.WriteRune(rune(code))
+					i += digits
+
+					continue
+				}
+			}
+
+			quoted.WriteRune('\\')
+			quoted.WriteRune(runes[i])
+		default:
+			quoted.WriteRune(runes[i])
+		}
+	}
+
+	return "", 0, ErrUnterminatedQuote
+}
+
+func indexRune(runes []rune, char rune, start int) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == char {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,139 @@
+package importer_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/importer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromCurl(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		input        string
+		opts         importer.CurlOptions
+		wantTargets  []app.Target
+		wantWarnings []string
+	}{
+		{
+			name:  "simple GET",
+			input: `curl https://api.example.com/v1/users?limit=10`,
+			wantTargets: []app.Target{
+				{RelativePath: "/v1/users?limit=10", HTTPMethod: "GET", ExpectedStatusCode: 200},
+			},
+			wantWarnings: []string{},
+		},
+		{
+			name: "multi line command with headers and JSON body",
+			input: `curl -X PUT 'https://api.example.com/v1/users/1' \
+  -H 'Content-Type: application/json' \
+  -H "Authorization: Bearer secret" \
+  --data-raw '{"name":"foo"}' \
+  --compressed -sS`,
+			opts: importer.CurlOptions{StripHeaders: importer.DefaultStripHeaders},
+			wantTargets: []app.Target{
+				{
+					RelativePath:       "/v1/users/1",
+					HTTPMethod:         "PUT",
+					ExpectedStatusCode: 200,
+					RequestBody:        stringPointer(`{"name":"foo"}`),
+					RequestHeaders:     map[string]string{"Content-Type": "application/json"},
+				},
+			},
+			wantWarnings: []string{},
+		},
+		{
+			name: "many commands",
+			input: `curl -d 'a=1' -d b=2 api.example.com/form
+$ curl --data-binary @body.json -H 'Content-Type: application/json' https://api.example.com/upload
+curl -G --data-urlencode 'q=foo bar' https://api.example.com/search
+curl -I https://api.example.com/health
+curl $'https://api.example.com/quoted?a=\x31' -XDELETE`,
+			wantTargets: []app.Target{
+				{
+					RelativePath:       "/form",
+					HTTPMethod:         "POST",
+					ExpectedStatusCode: 200,
+					RequestBody:        stringPointer("a=1&b=2"),
+					RequestHeaders:     map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+				},
+				{
+					RelativePath:       "/upload",
+					HTTPMethod:         "POST",
+					ExpectedStatusCode: 200,
+					RequestBodyFile:    stringPointer("body.json"),
+					RequestHeaders:     map[string]string{"Content-Type": "application/json"},
+				},
+				{RelativePath: "/search?q=foo+bar", HTTPMethod: "GET", ExpectedStatusCode: 200},
+				{RelativePath: "/health", HTTPMethod: "HEAD", ExpectedStatusCode: 200},
+				{RelativePath: "/quoted?a=1", HTTPMethod: "DELETE", ExpectedStatusCode: 200},
+			},
+			wantWarnings: []string{},
+		},
+		{
+			name: "braces in the query",
+			input: `curl 'https://api.example.com/graphql?query={me{id}}&x={a}'
+curl -G -d 'filter={a}' https://api.example.com/items`,
+			wantTargets: []app.Target{
+				{RelativePath: "/graphql?query=%7Bme%7Bid%7D%7D&x=%7Ba%7D", HTTPMethod: "GET", ExpectedStatusCode: 200},
+				{RelativePath: "/items?filter=%7Ba%7D", HTTPMethod: "GET", ExpectedStatusCode: 200},
+			},
+			wantWarnings: []string{},
+		},
+		{
+			name: "warnings",
+			input: `wget https://api.example.com
+curl -u user:secret --foo https://api.example.com/me
+curl -H 'Accept: application/json'`,
+			wantTargets: []app.Target{
+				{RelativePath: "/me", HTTPMethod: "GET", ExpectedStatusCode: 200},
+			},
+			wantWarnings: []string{
+				"command 1: not a curl command, skipping it",
+				"command 2: ignoring credentials of -u, use a headerFile instead",
+				"command 2: ignoring unsupported option --foo",
+				"command 3: no URL, skipping it",
+			},
+		},
+		{
+			name: "options taking a value",
+			input: `curl -F 'a=b' https://api.example.com/upload
+curl -T body.json -o out.json -m 5 --url-query page=1 https://api.example.com/files/1
+curl -sSLXPATCH -r 0-99 -Z https://api.example.com/range`,
+			wantTargets: []app.Target{
+				{RelativePath: "/upload", HTTPMethod: "POST", ExpectedStatusCode: 200},
+				{RelativePath: "/files/1", HTTPMethod: "PUT", ExpectedStatusCode: 200},
+				{RelativePath: "/range", HTTPMethod: "PATCH", ExpectedStatusCode: 200},
+			},
+			wantWarnings: []string{
+				"command 1: ignoring unsupported option -F, the request body is not imported",
+				"command 2: ignoring unsupported option -T, the request body is not imported",
+				"command 2: ignoring unsupported option --url-query",
+				"command 3: ignoring unsupported option -r",
+				"command 3: ignoring unsupported option -Z",
+			},
+		},
+		{
+			name:         "unterminated quote",
+			input:        `curl 'https://api.example.com`,
+			wantTargets:  []app.Target{},
+			wantWarnings: []string{"unterminated quote"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			urls, warnings := importer.FromCurl(tt.input, tt.opts)
+
+			assert.Equal(t, tt.wantTargets, urls.Targets)
+			assert.Equal(t, tt.wantWarnings, warnings)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/har"
+)
+
+var ErrEmptyHAR = errors.New("the HAR log has no entries")
+
+// DefaultStripHeaders are removed from imported requests: credentials and
+// headers the HTTP client sets itself.
+var DefaultStripHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Host",
+	"Content-Length",
+	"Connection",
+	"Accept-Encoding",
+}
+
+type HAROptions struct {
+	// Hosts limits the import to requests to these hosts, all if empty.
+	Hosts []string
+	// ContentTypes limits the import to responses with a content type
+	// containing one of these values, e.g. json. All if empty.
+	ContentTypes []string
+	// StripHeaders are removed from the requests (case-insensitive).
+	StripHeaders []string
+}
+
+// LoadHAR reads a HAR file.
+func LoadHAR(path string) (*har.HAR, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	var archive *har.HAR
+	if err := json.Unmarshal(content, &archive); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal %s file: %w", path, err)
+	}
+
+	if archive == nil || len(archive.Log.Entries) == 0 {
+		return nil, fmt.Errorf("%s: %w", path, ErrEmptyHAR)
+	}
+
+	return archive, nil
+}
+
+// FromHAR creates one target per entry of archive matching opts. The status
+// code of the recorded response becomes the expected status code, entries
+// without a response (status 0, e.g. blocked or aborted requests) are skipped.
+func FromHAR(archive *har.HAR, opts HAROptions) (*app.URLs, []string) {
+	urls := app.NewURLs([]app.Target{}, nil)
+	warnings := []string{}
+
+	for i, entry := range archive.Log.Entries {
+		requestURL, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("entry %d: invalid URL %q: %s", i, entry.Request.URL, err))
+
+			continue
+		}
+
+		if !matchesAny(requestURL.Host, opts.Hosts, strings.EqualFold) ||
+			!matchesAny(entry.Response.Content.MimeType, opts.ContentTypes, containsFold) {
+			continue
+		}
+
+		if entry.Response.Status == 0 {
+			warnings = append(warnings, fmt.Sprintf("entry %d: no response recorded for %s %s, skipped", i, entry.Request.Method, entry.Request.URL))
+
+			continue
+		}
+
+		target := app.Target{
+			RelativePath:       relativePath(requestURL),
+			HTTPMethod:         entry.Request.Method,
+			ExpectedStatusCode: entry.Response.Status,
+		}
+
+		for _, header := range entry.Request.Headers {
+			// HTTP/2 pseudo headers like :authority are never sent as headers
+			if strings.HasPrefix(header.Name, ":") || containsHeader(opts.StripHeaders, header.Name) {
+				continue
+			}
+
+			if target.RequestHeaders == nil {
+				target.RequestHeaders = map[string]string{}
+			}
+			target.RequestHeaders[header.Name] = header.Value
+		}
+
+		if entry.Request.PostData != nil && entry.Request.PostData.Text != "" {
+			body := entry.Request.PostData.Text
+			target.RequestBody = &body
+		}
+
+		urls.Targets = append(urls.Targets, target)
+	}
+
+	return urls, warnings
+}
+
+// queryBraces escapes braces in imported queries, they would be taken as path
+// expansions otherwise. Braces in paths are escaped by url.URL.EscapedPath.
+var queryBraces = strings.NewReplacer("{", "%7B", "}", "%7D")
+
+// relativePath returns the path and the query of u, with braces escaped.
+func relativePath(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	if u.RawQuery != "" {
+		path += "?" + queryBraces.Replace(u.RawQuery)
+	}
+
+	return path
+}
+
+// matchesAny reports whether value matches one of candidates, or candidates
+// is empty.
+func matchesAny(value string, candidates []string, match func(string, string) bool) bool {
+	if len(candidates) == 0 {
+		return true
+	}
+
+	for _, candidate := range candidates {
+		if match(value, candidate) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsHeader(headers []string, name string) bool {
+	for _, header := range headers {
+		if strings.EqualFold(header, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFold(value, substr string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(substr))
+}
@@ -0,0 +1,122 @@
+package importer_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/importer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromHAR(t *testing.T) {
+	t.Parallel()
+
+	archive, err := importer.LoadHAR("../.testdata/example.har")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		opts         importer.HAROptions
+		want         []app.Target
+		wantWarnings []string
+	}{
+		{
+			name: "all entries without stripped headers",
+			opts: importer.HAROptions{},
+			want: []app.Target{
+				{
+					RelativePath:       "/v1/users/1?fields=name&sort=asc",
+					HTTPMethod:         "GET",
+					ExpectedStatusCode: 200,
+					RequestHeaders: map[string]string{
+						"Accept":        "application/json",
+						"Authorization": "Bearer secret",
+						"Cookie":        "session=secret",
+					},
+				},
+				{
+					RelativePath:       "/logo.png",
+					HTTPMethod:         "GET",
+					ExpectedStatusCode: 200,
+				},
+				{
+					RelativePath:       "/v1/users",
+					HTTPMethod:         "POST",
+					ExpectedStatusCode: 422,
+					RequestBody:        stringPointer(`{"name":"foo"}`),
+					RequestHeaders: map[string]string{
+						"Content-Type":   "application/json",
+						"Content-Length": "14",
+					},
+				},
+				{
+					RelativePath:       "/v1/html",
+					HTTPMethod:         "GET",
+					ExpectedStatusCode: 200,
+				},
+				{
+					RelativePath:       "/graphql?query=%7Bme%7Bid%7D%7D&x=%7Ba%7D",
+					HTTPMethod:         "GET",
+					ExpectedStatusCode: 200,
+				},
+			},
+			wantWarnings: []string{
+				"entry 4: no response recorded for GET https://api.example.com/v1/blocked, skipped",
+			},
+		},
+		{
+			name: "filtered by host and content type, default headers stripped",
+			opts: importer.HAROptions{
+				Hosts:        []string{"API.example.com"},
+				ContentTypes: []string{"json"},
+				StripHeaders: importer.DefaultStripHeaders,
+			},
+			want: []app.Target{
+				{
+					RelativePath:       "/v1/users/1?fields=name&sort=asc",
+					HTTPMethod:         "GET",
+					ExpectedStatusCode: 200,
+					RequestHeaders:     map[string]string{"Accept": "application/json"},
+				},
+				{
+					RelativePath:       "/v1/users",
+					HTTPMethod:         "POST",
+					ExpectedStatusCode: 422,
+					RequestBody:        stringPointer(`{"name":"foo"}`),
+					RequestHeaders:     map[string]string{"Content-Type": "application/json"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			urls, warnings := importer.FromHAR(archive, tt.opts)
+
+			assert.Equal(t, tt.want, urls.Targets)
+			if tt.wantWarnings == nil {
+				assert.Empty(t, warnings)
+			} else {
+				assert.Equal(t, tt.wantWarnings, warnings)
+			}
+		})
+	}
+}
+
+func TestLoadHAR_Empty(t *testing.T) {
+	t.Parallel()
+
+	for _, content := range []string{`null`, `{"log": null}`, `{"log": {"entries": []}}`} {
+		path := filepath.Join(t.TempDir(), "empty.har")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+		_, err := importer.LoadHAR(path)
+		assert.ErrorIs(t, err, importer.ErrEmptyHAR, content)
+	}
+}
@@ -0,0 +1,143 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/openapi"
+)
+
+// expandableValue matches values that can be listed in a path expansion
+// without being mistaken for a range or a list separator.
+var expandableValue = regexp.MustCompile(`^[a-zA-Z0-9.]+$`)
+
+// FromOpenAPI creates one target per operation of doc. The paths start with
+// the base path of the first server of doc. Path parameters are filled with
+// their example values, multiple examples become a path expansion. Problems
+// that need manual fixes of the targets are returned as warnings.
+func FromOpenAPI(doc *openapi.Document) (*app.URLs, []string) {
+	urls := app.NewURLs([]app.Target{}, nil)
+	warnings := []string{}
+
+	basePath := ""
+	if len(doc.BasePaths) > 0 {
+		basePath = doc.BasePaths[0]
+		for _, otherBasePath := range doc.BasePaths[1:] {
+			if otherBasePath != basePath {
+				warnings = append(warnings, fmt.Sprintf(
+					"the servers have different base paths, using %s of the first one", basePath,
+				))
+
+				break
+			}
+		}
+	}
+
+	for _, operation := range doc.Operations {
+		target := app.Target{
+			RelativePath:       basePath + operation.Path,
+			HTTPMethod:         operation.Method,
+			ExpectedStatusCode: operation.ExpectedStatusCode(),
+		}
+
+		query := url.Values{}
+		for _, parameter := range operation.Parameters {
+			switch parameter.In {
+			case "path":
+				value, ok := pathParameterValue(parameter)
+				if !ok {
+					warnings = append(warnings, fmt.Sprintf(
+						"%s: no example for path parameter %q, using its name",
+						operation, parameter.Name,
+					))
+				}
+				target.RelativePath = strings.ReplaceAll(target.RelativePath, "{"+parameter.Name+"}", value)
+			case "query":
+				if !parameter.Required {
+					continue
+				}
+				if len(parameter.Examples) == 0 {
+					warnings = append(warnings, fmt.Sprintf(
+						"%s: no example for required query parameter %q",
+						operation, parameter.Name,
+					))
+
+					continue
+				}
+				query.Set(parameter.Name, fmt.Sprint(parameter.Examples[0]))
+			}
+		}
+		if len(query) > 0 {
+			target.RelativePath += "?" + query.Encode()
+		}
+
+		if operation.RequestBody != nil {
+			body, err := requestBodyExample(operation.RequestBody)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", operation, err))
+			}
+			if body != nil {
+				target.RequestBody = body
+				target.RequestHeaders = map[string]string{
+					"Content-Type": operation.RequestBody.ContentType,
+				}
+			}
+		}
+
+		urls.Targets = append(urls.Targets, target)
+	}
+
+	return urls, warnings
+}
+
+func pathParameterValue(parameter openapi.Parameter) (string, bool) {
+	if len(parameter.Examples) == 0 {
+		switch parameter.Schema["type"] {
+		case "integer", "number":
+			return "1", true
+		}
+
+		return url.PathEscape(parameter.Name), false
+	}
+
+	values := make([]string, 0, len(parameter.Examples))
+	for _, example := range parameter.Examples {
+		values = append(values, fmt.Sprint(example))
+	}
+
+	if len(values) > 1 {
+		expandable := true
+		for _, value := range values {
+			expandable = expandable && expandableValue.MatchString(value)
+		}
+
+		if expandable {
+			return "{" + strings.Join(values, ",") + "}", true
+		}
+	}
+
+	return url.PathEscape(values[0]), true
+}
+
+func requestBodyExample(requestBody *openapi.RequestBody) (*string, error) {
+	if len(requestBody.Examples) == 0 {
+		return nil, fmt.Errorf("no example for request body of type %s", requestBody.ContentType)
+	}
+
+	if str, ok := requestBody.Examples[0].(string); ok {
+		return &str, nil
+	}
+
+	body, err := json.Marshal(requestBody.Examples[0])
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode request body example: %w", err)
+	}
+
+	str := string(body)
+
+	return &str, nil
+}
@@ -0,0 +1,80 @@
+package importer_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/importer"
+	"github.com/phux/apijc/openapi"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	doc, err := openapi.Load("../.testdata/openapi.yaml")
+	assert.NoError(t, err)
+
+	urls, warnings := importer.FromOpenAPI(doc)
+
+	assert.Equal(t, []app.Target{
+		{
+			RelativePath:       "/v1/example",
+			HTTPMethod:         "GET",
+			ExpectedStatusCode: 200,
+		},
+		{
+			RelativePath:       "/v1/example",
+			HTTPMethod:         "POST",
+			ExpectedStatusCode: 201,
+			RequestBody:        stringPointer(`{"a":"b"}`),
+			RequestHeaders:     map[string]string{"Content-Type": "application/json"},
+		},
+		{
+			RelativePath:       "/v1/items/{1,2}?fields=name",
+			HTTPMethod:         "GET",
+			ExpectedStatusCode: 200,
+		},
+		{
+			RelativePath:       "/v1/users/userId/orders/1",
+			HTTPMethod:         "GET",
+			ExpectedStatusCode: 200,
+		},
+	}, urls.Targets)
+	assert.Equal(t, []string{
+		`GET /v1/users/{userId}/orders/{orderId}: no example for path parameter "userId", using its name`,
+	}, warnings)
+}
+
+func TestFromOpenAPIWithServerBasePath(t *testing.T) {
+	t.Parallel()
+
+	doc, err := openapi.Parse([]byte(`
+openapi: 3.0.3
+info:
+  title: Example API
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/api/v1/
+  - url: https://staging.example.com/v2
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: users
+`))
+	assert.NoError(t, err)
+
+	urls, warnings := importer.FromOpenAPI(doc)
+
+	assert.Equal(t, []app.Target{
+		{RelativePath: "/api/v1/users", HTTPMethod: "GET", ExpectedStatusCode: 200},
+	}, urls.Targets)
+	assert.Equal(t, []string{"the servers have different base paths, using /api/v1 of the first one"}, warnings)
+}
+
+func stringPointer(str string) *string {
+	return &str
+}
@@ -0,0 +1,345 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/phux/apijc/app"
+)
+
+var ErrUnsupportedPostmanCollection = errors.New("not a Postman v2.1 collection, `info.schema` is missing or not v2.1")
+
+// postmanVariable matches variable references like {{baseUrl}}.
+var postmanVariable = regexp.MustCompile(`{{\s*([^{}]+?)\s*}}`)
+
+// PostmanCollection is a Postman collection in the v2.1 format.
+// See https://schema.postman.com/collection/json/v2.1.0/draft-07/docs/index.html
+type PostmanCollection struct {
+	Info     PostmanInfo       `json:"info"`
+	Item     []PostmanItem     `json:"item"`
+	Variable []PostmanKeyValue `json:"variable"`
+}
+
+type PostmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// PostmanItem is either a folder (with items) or a request.
+type PostmanItem struct {
+	Name     string            `json:"name"`
+	Item     []PostmanItem     `json:"item"`
+	Request  *PostmanRequest   `json:"request"`
+	Response []PostmanResponse `json:"response"`
+}
+
+type PostmanRequest struct {
+	Method string              `json:"method"`
+	Header []PostmanKeyValue   `json:"header"`
+	URL    PostmanURL          `json:"url"`
+	Body   *PostmanRequestBody `json:"body"`
+}
+
+// PostmanURL is either a raw URL string or an object of URL parts.
+type PostmanURL struct {
+	Raw      string            `json:"raw"`
+	Path     []string          `json:"path"`
+	Query    []PostmanKeyValue `json:"query"`
+	Variable []PostmanKeyValue `json:"variable"`
+}
+
+type PostmanRequestBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw"`
+	URLEncoded []PostmanKeyValue `json:"urlencoded"`
+	Options    struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+}
+
+type PostmanResponse struct {
+	Code int `json:"code"`
+}
+
+type PostmanKeyValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+func (u *PostmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		u.Raw = raw
+
+		return nil
+	}
+
+	type plain PostmanURL
+
+	return json.Unmarshal(data, (*plain)(u))
+}
+
+// LoadPostman reads a Postman v2.1 collection.
+func LoadPostman(path string) (*PostmanCollection, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	var collection *PostmanCollection
+	if err := json.Unmarshal(content, &collection); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal %s file: %w", path, err)
+	}
+
+	if collection == nil || !strings.Contains(collection.Info.Schema, "v2.1") {
+		return nil, fmt.Errorf("%s: %w", path, ErrUnsupportedPostmanCollection)
+	}
+
+	return collection, nil
+}
+
+// FromPostman creates one target per request of collection. Requests at the
+// top level become targets, the requests of a folder become a sequential
+// group named by the folder (nested folders are joined with "/"). Collection
+// variables are resolved, the status code of the first saved example
+// response becomes the expected status code.
+func FromPostman(collection *PostmanCollection) (*app.URLs, []string) {
+	importer := postmanImporter{
+		urls:      app.NewURLs([]app.Target{}, map[string][]app.Target{}),
+		warnings:  []string{},
+		variables: map[string]string{},
+	}
+
+	for _, variable := range collection.Variable {
+		if !variable.Disabled {
+			importer.variables[variable.Key] = variable.Value
+		}
+	}
+
+	importer.importItems(collection.Item, "")
+
+	if len(importer.urls.SequentialTargets) == 0 {
+		importer.urls.SequentialTargets = nil
+	}
+
+	return importer.urls, importer.warnings
+}
+
+type postmanImporter struct {
+	urls      *app.URLs
+	warnings  []string
+	variables map[string]string
+}
+
+func (p *postmanImporter) importItems(items []PostmanItem, folder string) {
+	for _, item := range items {
+		if item.Request == nil {
+			name := item.Name
+			if folder != "" {
+				name = folder + "/" + item.Name
+			}
+			p.importItems(item.Item, name)
+
+			continue
+		}
+
+		target, unresolved := p.target(item)
+		if len(unresolved) > 0 {
+			p.warnings = append(p.warnings, fmt.Sprintf(
+				"%s: unresolved variables %s", item.Name, strings.Join(unresolved, ", "),
+			))
+		}
+
+		if folder == "" {
+			p.urls.Targets = append(p.urls.Targets, target)
+		} else {
+			p.urls.SequentialTargets[folder] = append(p.urls.SequentialTargets[folder], target)
+		}
+	}
+}
+
+// target converts the request of item and returns the names of the variables
+// that could not be resolved.
+func (p *postmanImporter) target(item PostmanItem) (app.Target, []string) {
+	request := item.Request
+	resolver := variableResolver{variables: p.variables}
+
+	method := request.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	target := app.Target{
+		RelativePath:       postmanRelativePath(request.URL, &resolver),
+		HTTPMethod:         strings.ToUpper(method),
+		ExpectedStatusCode: 200,
+	}
+
+	if len(item.Response) > 0 && item.Response[0].Code != 0 {
+		target.ExpectedStatusCode = item.Response[0].Code
+	}
+
+	headers := map[string]string{}
+	for _, header := range request.Header {
+		if !header.Disabled {
+			headers[resolver.resolve(header.Key)] = resolver.resolve(header.Value)
+		}
+	}
+
+	if request.Body != nil {
+		body, contentType, err := postmanBody(request.Body, &resolver)
+		if err != nil {
+			p.warnings = append(p.warnings, fmt.Sprintf("%s: %s", item.Name, err))
+		}
+
+		if body != nil {
+			target.RequestBody = body
+			if contentType != "" && !hasHeader(headers, "Content-Type") {
+				headers["Content-Type"] = contentType
+			}
+		}
+	}
+
+	if len(headers) > 0 {
+		target.RequestHeaders = headers
+	}
+
+	return target, resolver.unresolved
+}
+
+// postmanRelativePath returns the path and the query of u without the host.
+// Unresolved variables are escaped, their braces would be taken as path
+// expansions otherwise.
+func postmanRelativePath(u PostmanURL, resolver *variableResolver) string {
+	if u.Path == nil {
+		return postmanRawRelativePath(resolver.resolve(u.Raw))
+	}
+
+	pathVariables := map[string]string{}
+	for _, variable := range u.Variable {
+		pathVariables[variable.Key] = resolver.resolve(variable.Value)
+	}
+
+	segments := make([]string, 0, len(u.Path))
+	for _, segment := range u.Path {
+		if value, ok := pathVariables[strings.TrimPrefix(segment, ":")]; ok && strings.HasPrefix(segment, ":") {
+			segment = value
+		}
+		segments = append(segments, resolver.resolve(segment))
+	}
+
+	path := relativePath(&url.URL{Path: "/" + strings.Join(segments, "/")})
+
+	query := []string{}
+	for _, parameter := range u.Query {
+		if !parameter.Disabled {
+			query = append(query, url.QueryEscape(resolver.resolve(parameter.Key))+"="+url.QueryEscape(resolver.resolve(parameter.Value)))
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + strings.Join(query, "&")
+	}
+
+	return path
+}
+
+// postmanRawRelativePath returns the path and the query of a raw URL. The
+// scheme and the host are removed, also if the host is an unresolved variable
+// like {{baseUrl}}.
+func postmanRawRelativePath(raw string) string {
+	if _, rest, ok := strings.Cut(raw, "://"); ok {
+		raw = rest
+	}
+
+	if !strings.HasPrefix(raw, "/") {
+		// the host ends with the path or the query
+		end := strings.IndexAny(raw, "/?")
+		if end == -1 {
+			return "/"
+		}
+		raw = raw[end:]
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return queryBraces.Replace(raw)
+	}
+
+	return relativePath(parsed)
+}
+
+// postmanBody returns the request body and its content type.
+func postmanBody(body *PostmanRequestBody, resolver *variableResolver) (*string, string, error) {
+	switch body.Mode {
+	case "raw":
+		if body.Raw == "" {
+			return nil, "", nil
+		}
+
+		raw := resolver.resolve(body.Raw)
+		contentType := ""
+		if body.Options.Raw.Language == "json" {
+			contentType = "application/json"
+		}
+
+		return &raw, contentType, nil
+	case "urlencoded":
+		parameters := []string{}
+		for _, parameter := range body.URLEncoded {
+			if !parameter.Disabled {
+				parameters = append(parameters, url.QueryEscape(resolver.resolve(parameter.Key))+"="+url.QueryEscape(resolver.resolve(parameter.Value)))
+			}
+		}
+
+		encoded := strings.Join(parameters, "&")
+
+		return &encoded, "application/x-www-form-urlencoded", nil
+	case "":
+		return nil, "", nil
+	}
+
+	return nil, "", fmt.Errorf("request body mode %q is not supported, skipping the body", body.Mode)
+}
+
+// variableResolver replaces {{variable}} references and collects the names of
+// unknown variables.
+type variableResolver struct {
+	variables  map[string]string
+	unresolved []string
+}
+
+func (r *variableResolver) resolve(value string) string {
+	return postmanVariable.ReplaceAllStringFunc(value, func(reference string) string {
+		name := postmanVariable.FindStringSubmatch(reference)[1]
+		if resolved, ok := r.variables[name]; ok {
+			return resolved
+		}
+
+		for _, unresolved := range r.unresolved {
+			if unresolved == name {
+				return reference
+			}
+		}
+		r.unresolved = append(r.unresolved, name)
+
+		return reference
+	})
+}
+
+func hasHeader(headers map[string]string, name string) bool {
+	for header := range headers {
+		if strings.EqualFold(header, name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,71 @@
+package importer_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/app"
+	"github.com/phux/apijc/importer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromPostman(t *testing.T) {
+	t.Parallel()
+
+	collection, err := importer.LoadPostman("../.testdata/postman_collection.json")
+	assert.NoError(t, err)
+
+	urls, warnings := importer.FromPostman(collection)
+
+	assert.Equal(t, []app.Target{
+		{
+			RelativePath:       "/v1/users?limit=10",
+			HTTPMethod:         "GET",
+			ExpectedStatusCode: 200,
+			RequestHeaders:     map[string]string{"Accept": "application/json"},
+		},
+		{
+			RelativePath:       "/v1/items/%7B%7BitemId%7D%7D?filter=%7Ba%7D",
+			HTTPMethod:         "GET",
+			ExpectedStatusCode: 200,
+		},
+	}, urls.Targets)
+	assert.Equal(t, map[string][]app.Target{
+		"users": {
+			{
+				RelativePath:       "/v1/users",
+				HTTPMethod:         "POST",
+				ExpectedStatusCode: 201,
+				RequestBody:        stringPointer(`{"id":"42"}`),
+				RequestHeaders:     map[string]string{"Content-Type": "application/json"},
+			},
+			{
+				RelativePath:       "/v1/users/42?expand=orders",
+				HTTPMethod:         "GET",
+				ExpectedStatusCode: 200,
+				RequestHeaders:     map[string]string{"Authorization": "Bearer {{token}}"},
+			},
+		},
+		"users/login": {
+			{
+				RelativePath:       "/login",
+				HTTPMethod:         "POST",
+				ExpectedStatusCode: 200,
+				RequestBody:        stringPointer("user=foo+bar&password=secret"),
+				RequestHeaders:     map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			},
+		},
+	}, urls.SequentialTargets)
+	assert.Equal(t, []string{
+		"search items: unresolved variables otherUrl, itemId",
+		"get user: unresolved variables token",
+	}, warnings)
+}
+
+func TestLoadPostmanRejectsOtherFormats(t *testing.T) {
+	t.Parallel()
+
+	_, err := importer.LoadPostman("../.testdata/example.har")
+
+	assert.ErrorIs(t, err, importer.ErrUnsupportedPostmanCollection)
+}
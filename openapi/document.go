@@ -0,0 +1,417 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrNotOpenAPI3   = errors.New("not an OpenAPI 3 document, `openapi` version is missing or not 3.x")
+	ErrUnresolvedRef = errors.New("could not resolve $ref")
+)
+
+// methods are the HTTP methods an OpenAPI path item can define operations for.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Document is an OpenAPI 3 document.
+type Document struct {
+	Version    string
+	Operations []Operation
+	// BasePaths are the paths of the `servers` URLs, e.g. /v1.
+	BasePaths []string
+
+	raw      map[string]interface{}
+	compiler *jsonschema.Compiler
+	schemas  map[string]*jsonschema.Schema
+}
+
+// Operation is a single HTTP method on a path of the document.
+type Operation struct {
+	Method      string
+	Path        string
+	OperationID string
+	Parameters  []Parameter
+	RequestBody *RequestBody
+	// Responses are the documented status codes (e.g. 200, 4XX, default).
+	Responses []string
+
+	// pointer is the JSON pointer to the operation in the document.
+	pointer string
+	raw     map[string]interface{}
+}
+
+type Parameter struct {
+	Name     string
+	In       string
+	Required bool
+	Examples []interface{}
+	Schema   map[string]interface{}
+}
+
+type RequestBody struct {
+	ContentType string
+	Examples    []interface{}
+}
+
+// Load reads an OpenAPI 3 document in YAML or JSON format.
+func Load(path string) (*Document, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	return Parse(content)
+}
+
+// Parse parses an OpenAPI 3 document in YAML or JSON format.
+func Parse(content []byte) (*Document, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("cannot parse OpenAPI document: %w", err)
+	}
+
+	root, ok := normalizeYAML(raw).(map[string]interface{})
+	if !ok {
+		return nil, ErrNotOpenAPI3
+	}
+
+	version, _ := root["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, ErrNotOpenAPI3
+	}
+
+	doc := &Document{Version: version, raw: root}
+	doc.BasePaths = doc.basePaths()
+
+	if err := doc.parseOperations(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func (d *Document) parseOperations() error {
+	paths, _ := d.raw["paths"].(map[string]interface{})
+
+	for _, path := range sortedKeys(paths) {
+		pathItem, err := d.resolve(paths[path])
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		pathParameters, err := d.parseParameters(pathItem["parameters"])
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		for _, method := range methods {
+			rawOperation, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			operation, err := d.parseOperation(path, method, rawOperation, pathParameters)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+
+			d.Operations = append(d.Operations, operation)
+		}
+	}
+
+	return nil
+}
+
+func (d *Document) parseOperation(
+	path, method string,
+	raw map[string]interface{},
+	pathParameters []Parameter,
+) (Operation, error) {
+	operation := Operation{
+		Method:  strings.ToUpper(method),
+		Path:    path,
+		pointer: "/paths/" + escapePointer(path) + "/" + method,
+		raw:     raw,
+	}
+	operation.OperationID, _ = raw["operationId"].(string)
+
+	parameters, err := d.parseParameters(raw["parameters"])
+	if err != nil {
+		return operation, err
+	}
+	operation.Parameters = mergeParameters(pathParameters, parameters)
+
+	if raw["requestBody"] != nil {
+		requestBody, err := d.resolve(raw["requestBody"])
+		if err != nil {
+			return operation, err
+		}
+		operation.RequestBody = d.parseRequestBody(requestBody)
+	}
+
+	responses, _ := raw["responses"].(map[string]interface{})
+	operation.Responses = sortedKeys(responses)
+
+	return operation, nil
+}
+
+func (d *Document) parseParameters(raw interface{}) ([]Parameter, error) {
+	list, _ := raw.([]interface{})
+
+	parameters := []Parameter{}
+	for _, item := range list {
+		rawParameter, err := d.resolve(item)
+		if err != nil {
+			return nil, err
+		}
+
+		parameter := Parameter{}
+		parameter.Name, _ = rawParameter["name"].(string)
+		parameter.In, _ = rawParameter["in"].(string)
+		parameter.Required, _ = rawParameter["required"].(bool)
+		parameter.Schema, _ = d.resolve(rawParameter["schema"])
+		parameter.Examples = d.examples(rawParameter, parameter.Schema)
+
+		parameters = append(parameters, parameter)
+	}
+
+	return parameters, nil
+}
+
+func (d *Document) parseRequestBody(raw map[string]interface{}) *RequestBody {
+	content, _ := raw["content"].(map[string]interface{})
+
+	contentTypes := sortedKeys(content)
+	if len(contentTypes) == 0 {
+		return nil
+	}
+
+	// prefer JSON, as the request bodies are sent as they are
+	contentType := contentTypes[0]
+	for _, candidate := range contentTypes {
+		if isJSONContentType(candidate) {
+			contentType = candidate
+
+			break
+		}
+	}
+
+	mediaType, _ := content[contentType].(map[string]interface{})
+	schema, _ := d.resolve(mediaType["schema"])
+
+	return &RequestBody{
+		ContentType: contentType,
+		Examples:    d.examples(mediaType, schema),
+	}
+}
+
+// examples returns the `example`, the values of `examples` or the example of
+// the schema of an OpenAPI object, in this order.
+func (d *Document) examples(raw, schema map[string]interface{}) []interface{} {
+	if example, ok := raw["example"]; ok {
+		return []interface{}{example}
+	}
+
+	if examples, ok := raw["examples"].(map[string]interface{}); ok {
+		values := []interface{}{}
+		for _, name := range sortedKeys(examples) {
+			example, err := d.resolve(examples[name])
+			if err != nil {
+				continue
+			}
+			if value, ok := example["value"]; ok {
+				values = append(values, value)
+			}
+		}
+
+		if len(values) > 0 {
+			return values
+		}
+	}
+
+	if example, ok := schema["example"]; ok {
+		return []interface{}{example}
+	}
+
+	if examples, ok := schema["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples
+	}
+
+	return nil
+}
+
+// resolve returns raw as object, following local $refs.
+func (d *Document) resolve(raw interface{}) (map[string]interface{}, error) {
+	object, _ := raw.(map[string]interface{})
+
+	for depth := 0; object != nil; depth++ {
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return object, nil
+		}
+
+		if depth > 32 || !strings.HasPrefix(ref, "#/") {
+			return nil, fmt.Errorf("%q: %w", ref, ErrUnresolvedRef)
+		}
+
+		object = nil
+		if value, ok := d.lookup(ref[1:]); ok {
+			object, _ = value.(map[string]interface{})
+		}
+		if object == nil {
+			return nil, fmt.Errorf("%q: %w", ref, ErrUnresolvedRef)
+		}
+	}
+
+	return object, nil
+}
+
+// lookup returns the value at the JSON pointer in the document.
+func (d *Document) lookup(pointer string) (interface{}, bool) {
+	var node interface{} = d.raw
+	if pointer == "" {
+		return node, true
+	}
+
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	for _, segment := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		segment = unescape.Replace(segment)
+
+		switch typed := node.(type) {
+		case map[string]interface{}:
+			child, ok := typed[segment]
+			if !ok {
+				return nil, false
+			}
+			node = child
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(typed) {
+				return nil, false
+			}
+			node = typed[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return node, true
+}
+
+func (d *Document) basePaths() []string {
+	servers, _ := d.raw["servers"].([]interface{})
+
+	basePaths := []string{}
+	for _, server := range servers {
+		object, _ := server.(map[string]interface{})
+		serverURL, _ := object["url"].(string)
+
+		if i := strings.Index(serverURL, "://"); i >= 0 {
+			serverURL = serverURL[i+3:]
+			if j := strings.Index(serverURL, "/"); j >= 0 {
+				serverURL = serverURL[j:]
+			} else {
+				serverURL = ""
+			}
+		}
+
+		serverURL = strings.TrimRight(serverURL, "/")
+		if serverURL != "" {
+			basePaths = append(basePaths, serverURL)
+		}
+	}
+
+	return basePaths
+}
+
+// ExpectedStatusCode returns the first documented 2xx status code, 200 if
+// there is none.
+func (o Operation) ExpectedStatusCode() int {
+	for _, response := range o.Responses {
+		if len(response) == 3 && response[0] == '2' {
+			if code, err := strconv.Atoi(response); err == nil {
+				return code
+			}
+
+			return 200
+		}
+	}
+
+	return 200
+}
+
+// String returns the method and the path of the operation, e.g. GET /users.
+func (o Operation) String() string {
+	return o.Method + " " + o.Path
+}
+
+// mergeParameters returns the path item parameters, overridden by the
+// operation parameters with the same name and location.
+func mergeParameters(pathParameters, operationParameters []Parameter) []Parameter {
+	merged := []Parameter{}
+	for _, pathParameter := range pathParameters {
+		overridden := false
+		for _, operationParameter := range operationParameters {
+			if operationParameter.Name == pathParameter.Name && operationParameter.In == pathParameter.In {
+				overridden = true
+			}
+		}
+
+		if !overridden {
+			merged = append(merged, pathParameter)
+		}
+	}
+
+	return append(merged, operationParameters...)
+}
+
+// normalizeYAML converts the map[interface{}]interface{} YAML produces for
+// non-string keys (e.g. unquoted status codes) into map[string]interface{}.
+func normalizeYAML(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			typed[key] = normalizeYAML(child)
+		}
+
+		return typed
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(typed))
+		for key, child := range typed {
+			object[fmt.Sprint(key)] = normalizeYAML(child)
+		}
+
+		return object
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = normalizeYAML(child)
+		}
+	}
+
+	return value
+}
+
+func isJSONContentType(contentType string) bool {
+	return strings.Contains(contentType, "json")
+}
+
+func escapePointer(segment string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(segment)
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,89 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/openapi"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	doc, err := openapi.Load("../.testdata/openapi.yaml")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "3.0.3", doc.Version)
+
+	operations := []string{}
+	for _, operation := range doc.Operations {
+		operations = append(operations, operation.String())
+	}
+	assert.Equal(t, []string{
+		"GET /v1/example",
+		"POST /v1/example",
+		"GET /v1/items/{id}",
+		"GET /v1/users/{userId}/orders/{orderId}",
+	}, operations)
+
+	post := doc.Operations[1]
+	assert.Equal(t, "createExample", post.OperationID)
+	assert.Equal(t, []string{"201", "400"}, post.Responses)
+	assert.Equal(t, 201, post.ExpectedStatusCode())
+	assert.Equal(t, &openapi.RequestBody{
+		ContentType: "application/json",
+		Examples:    []interface{}{map[string]interface{}{"a": "b"}},
+	}, post.RequestBody)
+
+	item := doc.Operations[2]
+	assert.Len(t, item.Parameters, 2)
+	assert.Equal(t, "id", item.Parameters[0].Name)
+	assert.Equal(t, "path", item.Parameters[0].In)
+	assert.Equal(t, []interface{}{1, 2}, item.Parameters[0].Examples)
+	assert.Equal(t, "fields", item.Parameters[1].Name)
+	assert.True(t, item.Parameters[1].Required)
+
+	order := doc.Operations[3]
+	assert.Equal(t, []string{"default"}, order.Responses)
+	assert.Equal(t, 200, order.ExpectedStatusCode())
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{
+			name:    "swagger 2 is not supported",
+			content: `{"swagger": "2.0", "paths": {}}`,
+			wantErr: openapi.ErrNotOpenAPI3,
+		},
+		{
+			name:    "unresolvable ref",
+			content: `{"openapi": "3.1.0", "paths": {"/foo": {"get": {"parameters": [{"$ref": "#/components/parameters/Missing"}]}}}}`,
+			wantErr: openapi.ErrUnresolvedRef,
+		},
+		{
+			name:    "JSON document",
+			content: `{"openapi": "3.1.0", "paths": {"/foo": {"get": {"responses": {"204": {}}}}}}`,
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := openapi.Parse([]byte(tt.content))
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package openapi
+
+import (
+	"strings"
+)
+
+// FindOperation returns the operation matching the HTTP method and the
+// concrete path (e.g. /users/42?foo=bar). Paths are also matched with the
+// base paths of the servers of the document prepended. If multiple
+// operations match, the one with the most literal path segments wins.
+func (d *Document) FindOperation(method, path string) (Operation, bool) {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	segments := splitPath(path)
+
+	var (
+		best      Operation
+		bestScore = -1
+	)
+	for _, operation := range d.Operations {
+		if !strings.EqualFold(operation.Method, method) {
+			continue
+		}
+
+		for _, prefix := range append([]string{""}, d.BasePaths...) {
+			score, ok := matchPath(splitPath(prefix+operation.Path), segments)
+			if ok && score > bestScore {
+				best, bestScore = operation, score
+			}
+		}
+	}
+
+	return best, bestScore >= 0
+}
+
+// matchPath matches the segments of a path template against the segments of
+// a concrete path and returns the number of literal segments that matched.
+func matchPath(template, segments []string) (int, bool) {
+	if len(template) != len(segments) {
+		return 0, false
+	}
+
+	literals := 0
+	for i, segment := range template {
+		if isTemplated(segment) {
+			if segments[i] == "" {
+				return 0, false
+			}
+
+			continue
+		}
+
+		if segment != segments[i] {
+			return 0, false
+		}
+		literals++
+	}
+
+	return literals, true
+}
+
+func isTemplated(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
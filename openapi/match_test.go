@@ -0,0 +1,56 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/phux/apijc/openapi"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument_FindOperation(t *testing.T) {
+	t.Parallel()
+
+	doc, err := openapi.Parse([]byte(`
+openapi: 3.1.0
+servers:
+  - url: https://api.example.com/v2
+paths:
+  /users/{id}:
+    get: {}
+  /users/me:
+    get: {}
+  /users/{id}/orders/{orderId}:
+    get: {}
+    delete: {}
+`))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{method: "GET", path: "/users/42", expected: "GET /users/{id}"},
+		{method: "get", path: "/users/42?fields=name", expected: "GET /users/{id}"},
+		{method: "GET", path: "/users/me", expected: "GET /users/me"},
+		{method: "DELETE", path: "/users/1/orders/2", expected: "DELETE /users/{id}/orders/{orderId}"},
+		{method: "GET", path: "/v2/users/42", expected: "GET /users/{id}"},
+		{method: "POST", path: "/users/42", expected: ""},
+		{method: "GET", path: "/users", expected: ""},
+		{method: "GET", path: "/users/1/orders", expected: ""},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			operation, ok := doc.FindOperation(tt.method, tt.path)
+			assert.Equal(t, tt.expected != "", ok)
+			if ok {
+				assert.Equal(t, tt.expected, operation.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// documentURL is the URL the document is registered as in the JSON Schema
+// compiler, so $refs within the document resolve.
+const documentURL = "file:///openapi.json"
+
+// ResponseStatusDocumented reports whether the operation documents a response
+// for statusCode, explicitly, via a range (e.g. 2XX) or via default.
+func (o Operation) ResponseStatusDocumented(statusCode int) bool {
+	_, ok := o.responseKey(statusCode)
+
+	return ok
+}
+
+func (o Operation) responseKey(statusCode int) (string, bool) {
+	code := strconv.Itoa(statusCode)
+	candidates := []string{code, code[:1] + "XX", code[:1] + "xx", "default"}
+
+	for _, candidate := range candidates {
+		for _, response := range o.Responses {
+			if response == candidate {
+				return response, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// ResponseSchema returns the compiled JSON schema of the JSON response of the
+// operation for statusCode. It returns nil if the response has no JSON
+// schema.
+func (d *Document) ResponseSchema(operation Operation, statusCode int) (*jsonschema.Schema, error) {
+	key, ok := operation.responseKey(statusCode)
+	if !ok {
+		return nil, nil
+	}
+
+	responses, _ := operation.raw["responses"].(map[string]interface{})
+	response, err := d.resolve(responses[key])
+	if err != nil {
+		return nil, fmt.Errorf("%s response %s: %w", operation, key, err)
+	}
+
+	content, _ := response["content"].(map[string]interface{})
+	for _, contentType := range sortedKeys(content) {
+		if !isJSONContentType(contentType) {
+			continue
+		}
+
+		mediaType, _ := content[contentType].(map[string]interface{})
+		if _, ok := mediaType["schema"]; !ok {
+			continue
+		}
+
+		pointer := responsePointer(operation, responses[key], key, contentType)
+
+		return d.compileSchema(pointer)
+	}
+
+	return nil, nil
+}
+
+// responsePointer returns the JSON pointer to the schema of a response. If
+// the response is a $ref, the pointer follows it.
+func responsePointer(operation Operation, rawResponse interface{}, key, contentType string) string {
+	pointer := operation.pointer + "/responses/" + escapePointer(key)
+	if object, ok := rawResponse.(map[string]interface{}); ok {
+		if ref, ok := object["$ref"].(string); ok {
+			pointer = strings.TrimPrefix(ref, "#")
+		}
+	}
+
+	return pointer + "/content/" + escapePointer(contentType) + "/schema"
+}
+
+func (d *Document) compileSchema(pointer string) (*jsonschema.Schema, error) {
+	if schema, ok := d.schemas[pointer]; ok {
+		return schema, nil
+	}
+
+	if d.compiler == nil {
+		content, err := json.Marshal(d.schemaDocument())
+		if err != nil {
+			return nil, err
+		}
+
+		compiler := jsonschema.NewCompiler()
+		if err := compiler.AddResource(documentURL, bytes.NewReader(content)); err != nil {
+			return nil, err
+		}
+
+		d.compiler = compiler
+		d.schemas = map[string]*jsonschema.Schema{}
+	}
+
+	schema, err := d.compiler.Compile(documentURL + "#" + pointer)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compile schema %s: %w", pointer, err)
+	}
+	d.schemas[pointer] = schema
+
+	return schema, nil
+}
+
+// schemaDocument returns the document with the schemas of OpenAPI 3.0
+// converted to JSON Schema 2020-12: `nullable` becomes a "null" type, boolean
+// exclusiveMinimum/exclusiveMaximum become numbers. OpenAPI 3.1 schemas are
+// JSON Schema already.
+func (d *Document) schemaDocument() interface{} {
+	if !strings.HasPrefix(d.Version, "3.0") {
+		return d.raw
+	}
+
+	return convertSchema30(deepCopy(d.raw))
+}
+
+func convertSchema30(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			typed[key] = convertSchema30(child)
+		}
+
+		if nullable, _ := typed["nullable"].(bool); nullable {
+			if schemaType, ok := typed["type"].(string); ok {
+				typed["type"] = []interface{}{schemaType, "null"}
+			}
+		}
+
+		for exclusive, bound := range map[string]string{
+			"exclusiveMinimum": "minimum",
+			"exclusiveMaximum": "maximum",
+		} {
+			isExclusive, ok := typed[exclusive].(bool)
+			if !ok {
+				continue
+			}
+
+			delete(typed, exclusive)
+			if isExclusive {
+				if value, ok := typed[bound]; ok {
+					typed[exclusive] = value
+					delete(typed, bound)
+				}
+			}
+		}
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = convertSchema30(child)
+		}
+	}
+
+	return value
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(typed))
+		for key, child := range typed {
+			object[key] = deepCopy(child)
+		}
+
+		return object
+	case []interface{}:
+		list := make([]interface{}, len(typed))
+		for i, child := range typed {
+			list[i] = deepCopy(child)
+		}
+
+		return list
+	}
+
+	return value
+}